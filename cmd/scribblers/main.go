@@ -67,6 +67,10 @@ func main() {
 	}
 	frontendHandler.SetupRoutes(register)
 
+	if err := api.CreatePermanentLobbies(cfg); err != nil {
+		log.Fatal("error creating permanent rooms:", err)
+	}
+
 	if cfg.LobbyCleanup.Interval > 0 {
 		state.LaunchCleanupRoutine(cfg.LobbyCleanup)
 	}
@@ -1,10 +1,15 @@
 package api
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/scribble-rs/scribble.rs/internal/config"
 	"github.com/scribble-rs/scribble.rs/internal/game"
@@ -48,6 +53,73 @@ func ParseScoreCalculation(value string) (game.ScoreCalculation, error) {
 	return nil, errors.New("the given score calculation doesn't match any supported algorithm")
 }
 
+// ParseGameMode checks whether the given value is a supported game mode. An
+// empty value results in the classic mode.
+func ParseGameMode(value string) (game.GameMode, error) {
+	toLower := strings.ToLower(strings.TrimSpace(value))
+	switch toLower {
+	case "", string(game.ClassicMode):
+		return game.ClassicMode, nil
+	case string(game.WhiteboardMode):
+		return game.WhiteboardMode, nil
+	case string(game.TelephoneMode):
+		return game.TelephoneMode, nil
+	}
+
+	return "", errors.New("the given game mode doesn't match any supported mode")
+}
+
+// ParseDrawOrder checks whether the given value is part of the
+// game.SupportedDrawOrders. Empty strings will return the join order.
+func ParseDrawOrder(value string) (game.DrawOrder, error) {
+	toLower := strings.ToLower(strings.TrimSpace(value))
+	if toLower == "" {
+		return game.JoinDrawOrder, nil
+	}
+
+	if !slices.Contains(game.SupportedDrawOrders, toLower) {
+		return "", errors.New("the given draw order doesn't match any supported order")
+	}
+
+	return game.DrawOrder(toLower), nil
+}
+
+// ParseMinReadyPlayers checks whether the given value is an integer between 0
+// and the upper bound of max players. 0 means that all connected players
+// have to be ready. Empty strings will return 0.
+func ParseMinReadyPlayers(cfg *config.Config, value string) (int, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+
+	return parseIntValue(value, 0, cfg.LobbySettingBounds.MaxMaxPlayers, "min ready players")
+}
+
+// ParsePassword checks whether the given value is short enough to be used as
+// a lobby password. An empty value means that the lobby isn't protected.
+func ParsePassword(value string) (string, error) {
+	if utf8.RuneCountInString(value) > game.MaxPasswordLength {
+		return "", fmt.Errorf("the password can't be longer than %d characters", game.MaxPasswordLength)
+	}
+
+	return value, nil
+}
+
+// ParseInviteTokenValidity checks whether the given value is an amount of
+// seconds between 1 and the configured invite token validity. An empty
+// value returns the configured validity.
+func ParseInviteTokenValidity(cfg *config.Config, value string) (time.Duration, error) {
+	if value == "" {
+		return cfg.InviteTokenValidity, nil
+	}
+
+	seconds, err := parseIntValue(value, 1, int(cfg.InviteTokenValidity.Seconds()), "invite token validity")
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
 // ParseDrawingTime checks whether the given value is an integer between
 // the lower and upper bound of drawing time. All other invalid
 // input, including empty strings, will return an error.
@@ -117,6 +189,186 @@ func ParseWordsPerTurn(cfg *config.Config, value string) (int, error) {
 	return parseIntValue(value, 1, cfg.LobbySettingBounds.MaxWordsPerTurn, "words per turn")
 }
 
+// ParseDrawersPerTurn checks whether the given value is an integer between 1
+// and the upper bound of drawers per turn. Empty strings will return 1.
+func ParseDrawersPerTurn(cfg *config.Config, value string) (int, error) {
+	if strings.TrimSpace(value) == "" {
+		return 1, nil
+	}
+
+	return parseIntValue(value, 1, cfg.LobbySettingBounds.MaxDrawersPerTurn, "drawers per turn")
+}
+
+// ParseDrawerScore checks whether the given value is either "duplicate" or
+// "split" and returns whether the drawer score should be split between all
+// drawers of a turn. Empty strings will return false.
+func ParseDrawerScore(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "duplicate":
+		return false, nil
+	case "split":
+		return true, nil
+	}
+
+	return false, errors.New("the drawer score must be either 'duplicate' or 'split'")
+}
+
+// ParseGuessTimeCap checks whether the given value is an integer between 0
+// and the upper bound of the drawing time. 0 disables the cap. Empty strings
+// will return 0.
+func ParseGuessTimeCap(cfg *config.Config, value string) (int, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+
+	return parseIntValue(value, 0, cfg.LobbySettingBounds.MaxDrawingTime, "guess time cap")
+}
+
+// ParseGuessedPercentage checks whether the given value is an integer between
+// 0 and 100. 0 disables ending the turn early. Empty strings will return 0.
+func ParseGuessedPercentage(value string) (int, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+
+	return parseIntValue(value, 0, 100, "guessed percentage")
+}
+
+// ParseWordRerolls checks whether the given value is an integer between 0
+// and the upper bound of word rerolls per turn. Empty strings will return 1.
+func ParseWordRerolls(cfg *config.Config, value string) (int, error) {
+	if strings.TrimSpace(value) == "" {
+		return 1, nil
+	}
+
+	return parseIntValue(value, 0, cfg.LobbySettingBounds.MaxWordRerollsPerTurn, "word rerolls")
+}
+
+// ParseDrawerIdleTimeout checks whether the given value is an integer between
+// 0 and the upper bound of the drawing time. 0 disables the timeout. Empty
+// strings will return 0.
+func ParseDrawerIdleTimeout(cfg *config.Config, value string) (int, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+
+	return parseIntValue(value, 0, cfg.LobbySettingBounds.MaxDrawingTime, "drawer idle timeout")
+}
+
+// ParseIdleTurns checks whether the given value is an integer between 0 and
+// the upper bound of idle turns. 0 disables moving idle players to
+// spectating. Empty strings will return 0.
+func ParseIdleTurns(cfg *config.Config, value string) (int, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+
+	return parseIntValue(value, 0, cfg.LobbySettingBounds.MaxIdleTurns, "idle turns")
+}
+
+// ParseStartCountdown checks whether the given value is an integer between 0
+// and the upper bound of the start countdown. 0 starts the game without a
+// countdown. Empty strings will return 0.
+func ParseStartCountdown(cfg *config.Config, value string) (int, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+
+	return parseIntValue(value, 0, cfg.LobbySettingBounds.MaxStartCountdown, "start countdown")
+}
+
+// ParseFinalistsPerLobby checks whether the given value is an integer between
+// 1 and the upper bound of max players. Empty strings will return 2.
+func ParseFinalistsPerLobby(cfg *config.Config, value string) (int, error) {
+	if strings.TrimSpace(value) == "" {
+		return 2, nil
+	}
+
+	return parseIntValue(value, 1, cfg.LobbySettingBounds.MaxMaxPlayers, "finalists per lobby")
+}
+
+// ParseScheduledStart checks whether the given value is an RFC 3339
+// timestamp in the future, that isn't further ahead than the configured
+// maximum delay. Empty strings will return the zero time, meaning that the
+// lobby isn't scheduled.
+func ParseScheduledStart(cfg *config.Config, value string) (time.Time, error) {
+	if strings.TrimSpace(value) == "" {
+		return time.Time{}, nil
+	}
+
+	scheduledStart, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("the value 'scheduled start' must be an RFC 3339 timestamp, but was: '%s'", value)
+	}
+
+	now := time.Now()
+	if !scheduledStart.After(now) {
+		return time.Time{}, errors.New("the scheduled start must be in the future")
+	}
+	if scheduledStart.Sub(now) > cfg.MaxScheduleDelay {
+		return time.Time{}, fmt.Errorf("the scheduled start must be within %s", cfg.MaxScheduleDelay)
+	}
+
+	return scheduledStart, nil
+}
+
+// ParseAspectRatio checks whether the given value is one of the
+// game.SupportedAspectRatios. Empty strings will return the default aspect
+// ratio.
+func ParseAspectRatio(value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return game.DefaultAspectRatio, nil
+	}
+
+	if !game.IsSupportedAspectRatio(trimmed) {
+		return "", fmt.Errorf("the aspect ratio must be one of %s, but was: '%s'",
+			strings.Join(game.SupportedAspectRatios, ", "), value)
+	}
+
+	return trimmed, nil
+}
+
+// ParseBrushSize checks whether the given value is an integer between the
+// lower and upper bound of brush sizes. Empty strings will return the
+// given default value, moved into the bounds if necessary.
+func ParseBrushSize(cfg *config.Config, value string, defaultValue uint8, valueName string) (uint8, error) {
+	lower := cfg.LobbySettingBounds.MinMinBrushSize
+	upper := min(cfg.LobbySettingBounds.MaxMaxBrushSize, math.MaxUint8)
+	if strings.TrimSpace(value) == "" {
+		return uint8(max(lower, min(upper, int(defaultValue)))), nil
+	}
+
+	brushSize, err := parseIntValue(value, lower, upper, valueName)
+	return uint8(brushSize), err
+}
+
+// ParsePalette checks whether the given value is a comma separated list of
+// hex colors, such as "#ffffff,#000000". The leading hash is optional. The
+// first color is used as the canvas color. Empty strings will return a nil
+// palette and no error, meaning that the default palette is used.
+func ParsePalette(value string) ([]string, error) {
+	trimmedValue := strings.TrimSpace(value)
+	if trimmedValue == "" {
+		return nil, nil
+	}
+
+	palette := strings.Split(trimmedValue, ",")
+	if len(palette) < game.MinPaletteSize || len(palette) > game.MaxPaletteSize {
+		return nil, fmt.Errorf("the palette must contain between %d and %d colors", game.MinPaletteSize, game.MaxPaletteSize)
+	}
+
+	for index, color := range palette {
+		color = strings.TrimPrefix(strings.TrimSpace(color), "#")
+		if _, err := hex.DecodeString(color); err != nil || len(color) != 6 {
+			return nil, fmt.Errorf("the palette color '%s' isn't a valid hex color", palette[index])
+		}
+		palette[index] = "#" + strings.ToLower(color)
+	}
+
+	return palette, nil
+}
+
 func newIntOutOfBounds(value, valueName string, lower, upper int) error {
 	if upper != -1 {
 		return fmt.Errorf("the value '%s' must be an integer between %d and %d, but was: '%s'", valueName, lower, upper, value)
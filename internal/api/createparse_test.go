@@ -2,7 +2,10 @@ package api
 
 import (
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/scribble-rs/scribble.rs/internal/config"
 	"github.com/scribble-rs/scribble.rs/internal/game"
@@ -175,6 +178,394 @@ func Test_parseCustomWords(t *testing.T) {
 	}
 }
 
+func Test_parseAspectRatio(t *testing.T) {
+	t.Parallel()
+
+	aspectRatio, err := ParseAspectRatio("")
+	if err != nil || aspectRatio != game.DefaultAspectRatio {
+		t.Errorf("ParseAspectRatio() = %v, %v, want default", aspectRatio, err)
+	}
+
+	aspectRatio, err = ParseAspectRatio(" 9:16 ")
+	if err != nil || aspectRatio != "9:16" {
+		t.Errorf("ParseAspectRatio() = %v, %v, want 9:16", aspectRatio, err)
+	}
+
+	if _, err := ParseAspectRatio("21:9"); err == nil {
+		t.Error("ParseAspectRatio() expected error for unsupported aspect ratio")
+	}
+}
+
+func Test_parseGameMode(t *testing.T) {
+	t.Parallel()
+
+	gameMode, err := ParseGameMode("")
+	if err != nil || gameMode != game.ClassicMode {
+		t.Errorf("ParseGameMode() = %v, %v, want classic", gameMode, err)
+	}
+
+	gameMode, err = ParseGameMode(" Whiteboard ")
+	if err != nil || gameMode != game.WhiteboardMode {
+		t.Errorf("ParseGameMode() = %v, %v, want whiteboard", gameMode, err)
+	}
+
+	if _, err := ParseGameMode("battle-royale"); err == nil {
+		t.Error("ParseGameMode() expected error for unsupported game mode")
+	}
+}
+
+func Test_parseDrawersPerTurn(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"empty uses single drawer", "", 1, false},
+		{"single drawer", "1", 1, false},
+		{"maximum", "2", 2, false},
+		{"zero", "0", 0, true},
+		{"more than maximum", "3", 0, true},
+		{"not a number", "many", 0, true},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseDrawersPerTurn(&config.Default, testCase.value)
+			if (err != nil) != testCase.wantErr {
+				t.Errorf("ParseDrawersPerTurn() error = %v, wantErr %v", err, testCase.wantErr)
+				return
+			}
+			if got != testCase.want {
+				t.Errorf("ParseDrawersPerTurn() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func Test_parseDrawerScore(t *testing.T) {
+	t.Parallel()
+
+	split, err := ParseDrawerScore("")
+	if err != nil || split {
+		t.Errorf("ParseDrawerScore() = %v, %v, want duplicate", split, err)
+	}
+
+	split, err = ParseDrawerScore(" Split ")
+	if err != nil || !split {
+		t.Errorf("ParseDrawerScore() = %v, %v, want split", split, err)
+	}
+
+	if _, err := ParseDrawerScore("half"); err == nil {
+		t.Error("ParseDrawerScore() expected error for unsupported value")
+	}
+}
+
+func Test_parseGuessTimeCap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"empty disables cap", "", 0, false},
+		{"disabled", "0", 0, false},
+		{"something valid", "30", 30, false},
+		{"maximum", "300", 300, false},
+		{"more than maximum", "301", 0, true},
+		{"negative", "-1", 0, true},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseGuessTimeCap(&config.Default, testCase.value)
+			if (err != nil) != testCase.wantErr {
+				t.Errorf("ParseGuessTimeCap() error = %v, wantErr %v", err, testCase.wantErr)
+				return
+			}
+			if got != testCase.want {
+				t.Errorf("ParseGuessTimeCap() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func Test_parseGuessedPercentage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"empty disables rule", "", 0, false},
+		{"something valid", "75", 75, false},
+		{"maximum", "100", 100, false},
+		{"more than maximum", "101", 0, true},
+		{"not a number", "most", 0, true},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseGuessedPercentage(testCase.value)
+			if (err != nil) != testCase.wantErr {
+				t.Errorf("ParseGuessedPercentage() error = %v, wantErr %v", err, testCase.wantErr)
+				return
+			}
+			if got != testCase.want {
+				t.Errorf("ParseGuessedPercentage() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func Test_parseWordRerolls(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"empty allows single reroll", "", 1, false},
+		{"disabled", "0", 0, false},
+		{"maximum", "3", 3, false},
+		{"more than maximum", "4", 0, true},
+		{"negative", "-1", 0, true},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseWordRerolls(&config.Default, testCase.value)
+			if (err != nil) != testCase.wantErr {
+				t.Errorf("ParseWordRerolls() error = %v, wantErr %v", err, testCase.wantErr)
+				return
+			}
+			if got != testCase.want {
+				t.Errorf("ParseWordRerolls() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func Test_parseIdleSettings(t *testing.T) {
+	t.Parallel()
+
+	timeout, err := ParseDrawerIdleTimeout(&config.Default, "")
+	if err != nil || timeout != 0 {
+		t.Errorf("ParseDrawerIdleTimeout() = %v, %v, want 0", timeout, err)
+	}
+	timeout, err = ParseDrawerIdleTimeout(&config.Default, "20")
+	if err != nil || timeout != 20 {
+		t.Errorf("ParseDrawerIdleTimeout() = %v, %v, want 20", timeout, err)
+	}
+	if _, err := ParseDrawerIdleTimeout(&config.Default, "301"); err == nil {
+		t.Error("ParseDrawerIdleTimeout() expected error for value above drawing time")
+	}
+
+	turns, err := ParseIdleTurns(&config.Default, "")
+	if err != nil || turns != 0 {
+		t.Errorf("ParseIdleTurns() = %v, %v, want 0", turns, err)
+	}
+	turns, err = ParseIdleTurns(&config.Default, "3")
+	if err != nil || turns != 3 {
+		t.Errorf("ParseIdleTurns() = %v, %v, want 3", turns, err)
+	}
+	if _, err := ParseIdleTurns(&config.Default, "-1"); err == nil {
+		t.Error("ParseIdleTurns() expected error for negative value")
+	}
+}
+
+func Test_parseAutoStartSettings(t *testing.T) {
+	t.Parallel()
+
+	minReadyPlayers, err := ParseMinReadyPlayers(&config.Default, "")
+	if err != nil || minReadyPlayers != 0 {
+		t.Errorf("ParseMinReadyPlayers() = %v, %v, want 0", minReadyPlayers, err)
+	}
+	minReadyPlayers, err = ParseMinReadyPlayers(&config.Default, "3")
+	if err != nil || minReadyPlayers != 3 {
+		t.Errorf("ParseMinReadyPlayers() = %v, %v, want 3", minReadyPlayers, err)
+	}
+	if _, err := ParseMinReadyPlayers(&config.Default, "25"); err == nil {
+		t.Error("ParseMinReadyPlayers() expected error for value above max players")
+	}
+
+	countdown, err := ParseStartCountdown(&config.Default, "")
+	if err != nil || countdown != 0 {
+		t.Errorf("ParseStartCountdown() = %v, %v, want 0", countdown, err)
+	}
+	countdown, err = ParseStartCountdown(&config.Default, "10")
+	if err != nil || countdown != 10 {
+		t.Errorf("ParseStartCountdown() = %v, %v, want 10", countdown, err)
+	}
+	if _, err := ParseStartCountdown(&config.Default, "61"); err == nil {
+		t.Error("ParseStartCountdown() expected error for value above max countdown")
+	}
+}
+
+func Test_parseScheduledStart(t *testing.T) {
+	t.Parallel()
+
+	scheduledStart, err := ParseScheduledStart(&config.Default, "")
+	if err != nil || !scheduledStart.IsZero() {
+		t.Errorf("ParseScheduledStart() = %v, %v, want zero time", scheduledStart, err)
+	}
+
+	inOneHour := time.Now().Add(time.Hour).Truncate(time.Second)
+	scheduledStart, err = ParseScheduledStart(&config.Default, inOneHour.Format(time.RFC3339))
+	if err != nil || !scheduledStart.Equal(inOneHour) {
+		t.Errorf("ParseScheduledStart() = %v, %v, want %v", scheduledStart, err, inOneHour)
+	}
+
+	for _, value := range []string{
+		"friday",
+		time.Now().Add(-time.Hour).Format(time.RFC3339),
+		time.Now().Add(config.Default.MaxScheduleDelay + time.Hour).Format(time.RFC3339),
+	} {
+		if _, err := ParseScheduledStart(&config.Default, value); err == nil {
+			t.Errorf("ParseScheduledStart() expected error for %q", value)
+		}
+	}
+}
+
+func Test_parseDrawOrder(t *testing.T) {
+	t.Parallel()
+
+	drawOrder, err := ParseDrawOrder("")
+	if err != nil || drawOrder != game.JoinDrawOrder {
+		t.Errorf("ParseDrawOrder() = %v, %v, want join", drawOrder, err)
+	}
+
+	drawOrder, err = ParseDrawOrder(" Lowest_Score ")
+	if err != nil || drawOrder != game.LowestScoreDrawOrder {
+		t.Errorf("ParseDrawOrder() = %v, %v, want lowest_score", drawOrder, err)
+	}
+
+	if _, err := ParseDrawOrder("alphabetical"); err == nil {
+		t.Error("ParseDrawOrder() expected error for unsupported draw order")
+	}
+}
+
+func Test_parsePassword(t *testing.T) {
+	t.Parallel()
+
+	password, err := ParsePassword("")
+	if err != nil || password != "" {
+		t.Errorf("ParsePassword() = %v, %v, want empty password", password, err)
+	}
+	password, err = ParsePassword(" secret ")
+	if err != nil || password != " secret " {
+		t.Errorf("ParsePassword() = %v, %v, want untrimmed password", password, err)
+	}
+	if _, err := ParsePassword(strings.Repeat("ä", game.MaxPasswordLength)); err != nil {
+		t.Errorf("ParsePassword() unexpected error for password of max length: %v", err)
+	}
+	if _, err := ParsePassword(strings.Repeat("a", game.MaxPasswordLength+1)); err == nil {
+		t.Error("ParsePassword() expected error for too long password")
+	}
+}
+
+func Test_parseInviteTokenValidity(t *testing.T) {
+	t.Parallel()
+
+	validity, err := ParseInviteTokenValidity(&config.Default, "")
+	if err != nil || validity != config.Default.InviteTokenValidity {
+		t.Errorf("ParseInviteTokenValidity() = %v, %v, want default", validity, err)
+	}
+	validity, err = ParseInviteTokenValidity(&config.Default, "60")
+	if err != nil || validity != time.Minute {
+		t.Errorf("ParseInviteTokenValidity() = %v, %v, want 1m", validity, err)
+	}
+	if _, err := ParseInviteTokenValidity(&config.Default, "0"); err == nil {
+		t.Error("ParseInviteTokenValidity() expected error for 0")
+	}
+	tooLong := strconv.Itoa(int(config.Default.InviteTokenValidity.Seconds()) + 1)
+	if _, err := ParseInviteTokenValidity(&config.Default, tooLong); err == nil {
+		t.Error("ParseInviteTokenValidity() expected error for value above configured validity")
+	}
+}
+
+func Test_parseBrushSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		value        string
+		defaultValue uint8
+		want         uint8
+		wantErr      bool
+	}{
+		{"empty uses default", "", 8, 8, false},
+		{"default below bounds", "", 1, 4, false},
+		{"within bounds", "16", 8, 16, false},
+		{"lower bound", "4", 8, 4, false},
+		{"upper bound", "64", 8, 64, false},
+		{"below bounds", "3", 8, 0, true},
+		{"above bounds", "65", 8, 0, true},
+		{"not a number", "big", 8, 0, true},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseBrushSize(&config.Default, testCase.value, testCase.defaultValue, "brush size")
+			if (err != nil) != testCase.wantErr {
+				t.Errorf("ParseBrushSize() error = %v, wantErr %v", err, testCase.wantErr)
+				return
+			}
+			if got != testCase.want {
+				t.Errorf("ParseBrushSize() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func Test_parsePalette(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"spaces", "  ", nil, false},
+		{"two colors", "#FFFFFF,#000000", []string{"#ffffff", "#000000"}, false},
+		{"without hash and spaces", " ffffff , 00ff00", []string{"#ffffff", "#00ff00"}, false},
+		{"single color", "#ffffff", nil, true},
+		{"invalid hex", "#ffffff,#gggggg", nil, true},
+		{"short hex", "#fff,#000", nil, true},
+		{"empty color", "#ffffff,,#000000", nil, true},
+		{"too many colors", strings.Repeat("#ffffff,", game.MaxPaletteSize) + "#ffffff", nil, true},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParsePalette(testCase.value)
+			if (err != nil) != testCase.wantErr {
+				t.Errorf("ParsePalette() error = %v, wantErr %v", err, testCase.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("ParsePalette() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
 func Test_parseCustomWordsPerTurn(t *testing.T) {
 	t.Parallel()
 
@@ -289,3 +680,19 @@ func Test_parseBoolean(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseFinalistsPerLobby(t *testing.T) {
+	t.Parallel()
+
+	finalists, err := ParseFinalistsPerLobby(&config.Default, "")
+	if err != nil || finalists != 2 {
+		t.Errorf("ParseFinalistsPerLobby() = %v, %v, want 2", finalists, err)
+	}
+	finalists, err = ParseFinalistsPerLobby(&config.Default, "4")
+	if err != nil || finalists != 4 {
+		t.Errorf("ParseFinalistsPerLobby() = %v, %v, want 4", finalists, err)
+	}
+	if _, err := ParseFinalistsPerLobby(&config.Default, "0"); err == nil {
+		t.Error("ParseFinalistsPerLobby() expected error for zero finalists")
+	}
+}
@@ -21,6 +21,7 @@ func (handler *V1Handler) SetupRoutes(rootPath string, register func(string, str
 	// backwards compatibility as far as possible.
 	register("GET", path.Join(v1, "lobby"), handler.getLobbies)
 	register("POST", path.Join(v1, "lobby"), handler.postLobby)
+	register("POST", path.Join(v1, "matchmake"), handler.postMatchmake)
 
 	register("PATCH", path.Join(v1, "lobby", "{lobby_id}"), handler.patchLobby)
 	// We support both path parameter and cookie.
@@ -32,6 +33,26 @@ func (handler *V1Handler) SetupRoutes(rootPath string, register func(string, str
 	register("GET", path.Join(v1, "lobby", "ws"), handler.websocketUpgrade)
 
 	register("POST", path.Join(v1, "lobby", "{lobby_id}", "player"), handler.postPlayer)
+	register("GET", path.Join(v1, "lobby", "{lobby_id}", "results"), handler.getResults)
+
+	register("POST", path.Join(v1, "lobby", "{lobby_id}", "invite"), handler.postInvite)
+	register("DELETE", path.Join(v1, "lobby", "{lobby_id}", "invite", "{token}"), handler.deleteInvite)
+	// We support both path parameter and cookie.
+	register("POST", path.Join(v1, "lobby", "invite"), handler.postInvite)
+	register("DELETE", path.Join(v1, "lobby", "invite", "{token}"), handler.deleteInvite)
+
+	register("POST", path.Join(v1, "lobby", "{lobby_id}", "clone"), handler.postClone)
+	// We support both path parameter and cookie.
+	register("POST", path.Join(v1, "lobby", "clone"), handler.postClone)
+
+	register("POST", path.Join(v1, "tournament"), handler.postTournament)
+	register("GET", path.Join(v1, "tournament", "{tournament_id}"), handler.getTournament)
+	register("DELETE", path.Join(v1, "tournament", "{tournament_id}"), handler.deleteTournament)
+	register("POST", path.Join(v1, "tournament", "{tournament_id}", "start"), handler.postTournamentStart)
+	register("POST", path.Join(v1, "tournament", "{tournament_id}", "advance"), handler.postTournamentAdvance)
+	register("POST", path.Join(v1, "tournament", "{tournament_id}", "participant"), handler.postTournamentParticipant)
+	register("POST", path.Join(v1, "tournament", "{tournament_id}", "participant", "{participant_id}", "enter"),
+		handler.postTournamentEnter)
 }
 
 // remoteAddressToSimpleIP removes unnecessary clutter from the input,
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/scribble-rs/scribble.rs/internal/config"
+	"github.com/scribble-rs/scribble.rs/internal/game"
+)
+
+// createLobbyFromDefaults creates a lobby without any players, using the
+// server side lobby setting defaults. Each setting can be overwritten by
+// passing a value with the same key as used for lobby creation requests.
+// Custom words of the defaults aren't used, as the lobbies created by the
+// server are meant for strangers. For the same reason, passwords and
+// scheduled starts are ignored.
+func createLobbyFromDefaults(cfg *config.Config, overrides url.Values) (*game.Lobby, error) {
+	defaults := cfg.LobbySettingDefaults
+	defaults.CustomWords = ""
+
+	settings, requestErrors := ParseLobbySettings(cfg, overrides, defaults)
+	if len(requestErrors) != 0 {
+		return nil, errors.New(strings.Join(requestErrors, ";"))
+	}
+	settings.ScheduledStart = time.Time{}
+	settings.Password = ""
+
+	lobby := game.NewLobby("", settings.LanguageKey, &settings.Editable,
+		settings.CustomWords, settings.ScoreCalculation, settings.GameMode)
+	if err := ApplyLobbySettings(cfg, lobby, settings); err != nil {
+		return nil, err
+	}
+
+	return lobby, nil
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/scribble-rs/scribble.rs/internal/config"
+	"github.com/scribble-rs/scribble.rs/internal/game"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// LobbySettings are all settings that can be passed when creating a lobby.
+type LobbySettings struct {
+	LanguageKey      string
+	CustomWords      []string
+	ScoreCalculation game.ScoreCalculation
+	GameMode         game.GameMode
+	Editable         game.EditableLobbySettings
+	Palette          []string
+	Canvas           game.CanvasSettings
+	Gameplay         game.GameplaySettings
+	ScheduledStart   time.Time
+	Password         string
+}
+
+// ParseLobbySettings parses and cross-validates all lobby settings from the
+// given values. Settings that aren't part of the values are taken from the
+// defaults. Passing empty defaults therefore means that each setting has to
+// be passed explicitly, unless its parser falls back to a default itself.
+// All validation errors are returned, so they can be shown at once.
+func ParseLobbySettings(
+	cfg *config.Config,
+	values url.Values,
+	defaults config.LobbySettingDefaults,
+) (*LobbySettings, []string) {
+	settingValue := func(key, defaultValue string) string {
+		if values.Has(key) {
+			return values.Get(key)
+		}
+		return defaultValue
+	}
+
+	scoreCalculation, scoreCalculationInvalid := ParseScoreCalculation(settingValue("score_calculation", defaults.ScoreCalculation))
+	gameMode, gameModeInvalid := ParseGameMode(settingValue("game_mode", defaults.GameMode))
+	languageRawValue := strings.ToLower(strings.TrimSpace(settingValue("language", defaults.Language)))
+	languageData, languageKey, languageInvalid := ParseLanguage(languageRawValue)
+	drawingTime, drawingTimeInvalid := ParseDrawingTime(cfg, settingValue("drawing_time", defaults.DrawingTime))
+	rounds, roundsInvalid := ParseRounds(cfg, settingValue("rounds", defaults.Rounds))
+	maxPlayers, maxPlayersInvalid := ParseMaxPlayers(cfg, settingValue("max_players", defaults.MaxPlayers))
+	customWordsPerTurn, customWordsPerTurnInvalid := ParseCustomWordsPerTurn(cfg, settingValue("custom_words_per_turn", defaults.CustomWordsPerTurn))
+	clientsPerIPLimit, clientsPerIPLimitInvalid := ParseClientsPerIPLimit(cfg, settingValue("clients_per_ip_limit", defaults.ClientsPerIPLimit))
+	publicLobby, publicLobbyInvalid := ParseBoolean("public", settingValue("public", defaults.Public))
+	wordsPerTurn, wordsPerTurnInvalid := ParseWordsPerTurn(cfg, settingValue("words_per_turn", defaults.WordsPerTurn))
+	palette, paletteInvalid := ParsePalette(values.Get("palette"))
+	aspectRatio, aspectRatioInvalid := ParseAspectRatio(settingValue("aspect_ratio", defaults.AspectRatio))
+	minBrushSize, minBrushSizeInvalid := ParseBrushSize(cfg, settingValue("min_brush_size", defaults.MinBrushSize), game.MinBrushSize, "min brush size")
+	maxBrushSize, maxBrushSizeInvalid := ParseBrushSize(cfg, settingValue("max_brush_size", defaults.MaxBrushSize), game.MaxBrushSize, "max brush size")
+	drawersPerTurn, drawersPerTurnInvalid := ParseDrawersPerTurn(cfg, settingValue("drawers_per_turn", defaults.DrawersPerTurn))
+	splitDrawerScore, drawerScoreInvalid := ParseDrawerScore(settingValue("drawer_score", defaults.DrawerScore))
+	guessTimeCap, guessTimeCapInvalid := ParseGuessTimeCap(cfg, settingValue("guess_time_cap", defaults.GuessTimeCap))
+	guessedPercentage, guessedPercentageInvalid := ParseGuessedPercentage(settingValue("guessed_percentage", defaults.GuessedPercentage))
+	wordRerolls, wordRerollsInvalid := ParseWordRerolls(cfg, settingValue("word_rerolls", defaults.WordRerolls))
+	drawerIdleTimeout, drawerIdleTimeoutInvalid := ParseDrawerIdleTimeout(cfg, settingValue("drawer_idle_timeout", defaults.DrawerIdleTimeout))
+	idleTurns, idleTurnsInvalid := ParseIdleTurns(cfg, settingValue("idle_turns", defaults.IdleTurns))
+	drawOrder, drawOrderInvalid := ParseDrawOrder(settingValue("draw_order", defaults.DrawOrder))
+	minReadyPlayers, minReadyPlayersInvalid := ParseMinReadyPlayers(cfg, settingValue("min_ready_players", defaults.MinReadyPlayers))
+	startCountdown, startCountdownInvalid := ParseStartCountdown(cfg, settingValue("start_countdown", defaults.StartCountdown))
+	scheduledStart, scheduledStartInvalid := ParseScheduledStart(cfg, values.Get("scheduled_start"))
+	password, passwordInvalid := ParsePassword(values.Get("password"))
+
+	if minBrushSizeInvalid == nil && maxBrushSizeInvalid == nil && minBrushSize > maxBrushSize {
+		maxBrushSizeInvalid = errors.New("max brush size must be greater than or equal to min brush size")
+	}
+
+	if wordsPerTurn < customWordsPerTurn {
+		wordsPerTurnInvalid = errors.New("words per turn must be greater than or equal to custom words per turn")
+	}
+
+	if minReadyPlayersInvalid == nil && maxPlayersInvalid == nil && minReadyPlayers > maxPlayers {
+		minReadyPlayersInvalid = errors.New("min ready players must be less than or equal to max players")
+	}
+
+	var lowercaser cases.Caser
+	if languageInvalid != nil {
+		lowercaser = cases.Lower(language.English)
+	} else {
+		lowercaser = languageData.Lowercaser()
+	}
+
+	customWords, customWordsInvalid := ParseCustomWords(lowercaser, settingValue("custom_words", defaults.CustomWords))
+
+	var customWordsMissing error
+	if customWordsPerTurnInvalid == nil && languageRawValue == "custom" && len(customWords) == 0 {
+		customWordsMissing = errors.New("custom words must be provided when using custom language")
+	}
+
+	var requestErrors []string
+	for _, err := range []error{
+		scoreCalculationInvalid, gameModeInvalid, languageInvalid, drawingTimeInvalid,
+		roundsInvalid, maxPlayersInvalid, customWordsInvalid, customWordsPerTurnInvalid,
+		customWordsMissing, clientsPerIPLimitInvalid, publicLobbyInvalid, wordsPerTurnInvalid,
+		paletteInvalid, aspectRatioInvalid, minBrushSizeInvalid, maxBrushSizeInvalid,
+		drawersPerTurnInvalid, drawerScoreInvalid, guessTimeCapInvalid, guessedPercentageInvalid,
+		wordRerollsInvalid, drawerIdleTimeoutInvalid, idleTurnsInvalid, drawOrderInvalid,
+		minReadyPlayersInvalid, startCountdownInvalid, scheduledStartInvalid, passwordInvalid,
+	} {
+		if err != nil {
+			requestErrors = append(requestErrors, err.Error())
+		}
+	}
+	if len(requestErrors) != 0 {
+		return nil, requestErrors
+	}
+
+	return &LobbySettings{
+		LanguageKey:      languageKey,
+		CustomWords:      customWords,
+		ScoreCalculation: scoreCalculation,
+		GameMode:         gameMode,
+		Editable: game.EditableLobbySettings{
+			Rounds:             rounds,
+			DrawingTime:        drawingTime,
+			MaxPlayers:         maxPlayers,
+			CustomWordsPerTurn: customWordsPerTurn,
+			ClientsPerIPLimit:  clientsPerIPLimit,
+			Public:             publicLobby,
+			WordsPerTurn:       wordsPerTurn,
+		},
+		Palette: palette,
+		Canvas: game.CanvasSettings{
+			AspectRatio:  aspectRatio,
+			MinBrushSize: minBrushSize,
+			MaxBrushSize: maxBrushSize,
+		},
+		Gameplay: game.GameplaySettings{
+			DrawersPerTurn:         drawersPerTurn,
+			SplitDrawerScore:       splitDrawerScore,
+			GuessTimeCap:           guessTimeCap,
+			GuessedPercentageToEnd: guessedPercentage,
+			DrawOrder:              drawOrder,
+			WordRerollsPerTurn:     wordRerolls,
+			DrawerIdleTimeout:      drawerIdleTimeout,
+			IdleTurnsToSpectate:    idleTurns,
+			MinReadyPlayers:        minReadyPlayers,
+			StartCountdown:         startCountdown,
+		},
+		ScheduledStart: scheduledStart,
+		Password:       password,
+	}, nil
+}
+
+// ApplyLobbySettings applies all settings to a freshly created lobby, that
+// can't be passed on creation, and wires up the server side callbacks.
+func ApplyLobbySettings(cfg *config.Config, lobby *game.Lobby, settings *LobbySettings) error {
+	lobby.WriteObject = WriteObject
+	lobby.WritePreparedMessage = WritePreparedMessage
+	lobby.DrawingBatchInterval = cfg.DrawingBatchInterval
+	lobby.Palette = settings.Palette
+	lobby.CanvasSettings = settings.Canvas
+	lobby.GameplaySettings = settings.Gameplay
+	if !settings.ScheduledStart.IsZero() {
+		lobby.ScheduleStart(settings.ScheduledStart)
+	}
+	return lobby.SetPassword(settings.Password)
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/scribble-rs/scribble.rs/internal/config"
+)
+
+func Test_parseLobbySettings(t *testing.T) {
+	t.Parallel()
+
+	values := url.Values{}
+	values.Set("drawing_time", "90")
+	settings, errs := ParseLobbySettings(&config.Default, values, config.Default.LobbySettingDefaults)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors parsing settings: %v", errs)
+	}
+	if settings.Editable.DrawingTime != 90 {
+		t.Errorf("expected drawing time of 90, got %d", settings.Editable.DrawingTime)
+	}
+	if settings.LanguageKey != "english" {
+		t.Errorf("expected default language english, got %s", settings.LanguageKey)
+	}
+
+	// Without defaults, required settings have to be passed.
+	if _, errs := ParseLobbySettings(&config.Default, values, config.LobbySettingDefaults{}); len(errs) == 0 {
+		t.Error("expected errors when parsing settings without defaults")
+	}
+
+	for _, query := range []string{
+		"min_brush_size=20&max_brush_size=10",
+		"min_ready_players=30&max_players=4",
+		"language=custom",
+	} {
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			t.Fatalf("error parsing query %q: %s", query, err)
+		}
+		if _, errs := ParseLobbySettings(&config.Default, values, config.Default.LobbySettingDefaults); len(errs) == 0 {
+			t.Errorf("expected errors for %q", query)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/scribble-rs/scribble.rs/internal/game"
+	"github.com/scribble-rs/scribble.rs/internal/state"
+)
+
+// matchmakingPreferences are the criteria used for choosing a lobby. Only
+// lobbies matching the language, game mode and scoring (if set) are
+// considered. The drawing time merely influences which lobby is preferred.
+type matchmakingPreferences struct {
+	languageKey string
+	gameMode    game.GameMode
+	// scoreCalculation is nil, if any scoring is fine.
+	scoreCalculation game.ScoreCalculation
+	// drawingTime is 0, if any drawing time is fine.
+	drawingTime int
+}
+
+func (handler *V1Handler) parseMatchmakingPreferences(request *http.Request) (*matchmakingPreferences, []string) {
+	var requestErrors []string
+
+	languageValue := request.Form.Get("language")
+	if strings.TrimSpace(languageValue) == "" {
+		languageValue = handler.cfg.LobbySettingDefaults.Language
+	}
+	_, languageKey, languageInvalid := ParseLanguage(languageValue)
+	if languageInvalid != nil {
+		requestErrors = append(requestErrors, languageInvalid.Error())
+	}
+
+	gameMode, gameModeInvalid := ParseGameMode(request.Form.Get("game_mode"))
+	if gameModeInvalid != nil {
+		requestErrors = append(requestErrors, gameModeInvalid.Error())
+	}
+
+	preferences := &matchmakingPreferences{
+		languageKey: languageKey,
+		gameMode:    gameMode,
+	}
+
+	if value := request.Form.Get("score_calculation"); value != "" {
+		scoreCalculation, scoreCalculationInvalid := ParseScoreCalculation(value)
+		if scoreCalculationInvalid != nil {
+			requestErrors = append(requestErrors, scoreCalculationInvalid.Error())
+		}
+		preferences.scoreCalculation = scoreCalculation
+	}
+
+	if value := request.Form.Get("drawing_time"); value != "" {
+		drawingTime, drawingTimeInvalid := ParseDrawingTime(handler.cfg, value)
+		if drawingTimeInvalid != nil {
+			requestErrors = append(requestErrors, drawingTimeInvalid.Error())
+		}
+		preferences.drawingTime = drawingTime
+	}
+
+	return preferences, requestErrors
+}
+
+// matchScore rates how well the lobby fits the preferences, the higher the
+// better. Lobbies that can't be joined by the given address or don't match
+// the preferences aren't a match at all. The lobby has to be locked.
+func matchScore(lobby *game.Lobby, preferences *matchmakingPreferences, address string) (int, bool) {
+	if !lobby.IsPublic() || lobby.HasPassword() || lobby.State == game.GameOver ||
+		lobby.Wordpack != preferences.languageKey || lobby.GameMode != preferences.gameMode {
+		return 0, false
+	}
+	if preferences.scoreCalculation != nil &&
+		lobby.ScoreCalculation.Identifier() != preferences.scoreCalculation.Identifier() {
+		return 0, false
+	}
+	if !lobby.HasFreePlayerSlot() || !lobby.CanIPConnect(address) {
+		return 0, false
+	}
+
+	// Playing with more people is more fun, so we try to fill up lobbies.
+	score := lobby.GetConnectedPlayerCount() * 10
+	if lobby.State == game.Unstarted {
+		score += 50
+	} else if lobby.Rounds > 0 {
+		// Joining shortly before the game ends isn't much fun.
+		score -= 50 * lobby.Round / lobby.Rounds
+	}
+	if preferences.drawingTime > 0 {
+		difference := lobby.DrawingTime - preferences.drawingTime
+		score -= max(difference, -difference) / 10
+	}
+
+	return score, true
+}
+
+// findMatchingLobbies returns all lobbies matching the preferences, ordered
+// from best to worst match.
+func findMatchingLobbies(
+	lobbies []*game.Lobby,
+	preferences *matchmakingPreferences,
+	address string,
+) []*game.Lobby {
+	type match struct {
+		lobby *game.Lobby
+		score int
+	}
+
+	var matches []match
+	for _, lobby := range lobbies {
+		lobby.Synchronized(func() {
+			if score, ok := matchScore(lobby, preferences, address); ok {
+				matches = append(matches, match{lobby: lobby, score: score})
+			}
+		})
+	}
+
+	// Stable sorting makes older lobbies win on equal scores.
+	slices.SortStableFunc(matches, func(a, b match) int {
+		return b.score - a.score
+	})
+
+	result := make([]*game.Lobby, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, match.lobby)
+	}
+	return result
+}
+
+// createMatchmakingLobby creates a new public lobby using the server side
+// lobby setting defaults and the preferences. The given player is the owner.
+func (handler *V1Handler) createMatchmakingLobby(
+	preferences *matchmakingPreferences,
+	playerName string,
+) (*game.Player, *game.Lobby, error) {
+	overrides := url.Values{}
+	overrides.Set("public", "true")
+	overrides.Set("language", preferences.languageKey)
+	overrides.Set("game_mode", string(preferences.gameMode))
+	if preferences.scoreCalculation != nil {
+		overrides.Set("score_calculation", preferences.scoreCalculation.Identifier())
+	}
+	if preferences.drawingTime > 0 {
+		overrides.Set("drawing_time", strconv.Itoa(preferences.drawingTime))
+	}
+
+	lobby, err := createLobbyFromDefaults(handler.cfg, overrides)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	player := lobby.JoinPlayer(playerName)
+	lobby.OwnerID = player.ID
+
+	return player, lobby, nil
+}
+
+// postMatchmake places the caller into the public lobby best fitting their
+// preferences. If there's no such lobby, a new public lobby is created.
+func (handler *V1Handler) postMatchmake(writer http.ResponseWriter, request *http.Request) {
+	if err := request.ParseForm(); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	preferences, requestErrors := handler.parseMatchmakingPreferences(request)
+	if len(requestErrors) != 0 {
+		http.Error(writer, strings.Join(requestErrors, ";"), http.StatusBadRequest)
+		return
+	}
+
+	requestAddress := GetIPAddressFromRequest(request)
+	playerName := GetPlayername(request)
+
+	var lobbyData *LobbyData
+	for _, lobby := range findMatchingLobbies(state.GetPublicLobbies(), preferences, requestAddress) {
+		lobby.Synchronized(func() {
+			// The lobby might have changed since we've rated it.
+			if _, ok := matchScore(lobby, preferences, requestAddress); !ok {
+				return
+			}
+
+			newPlayer := lobby.JoinPlayer(playerName)
+			newPlayer.SetLastKnownAddress(requestAddress)
+			SetGameplayCookies(writer, request, newPlayer, lobby)
+			lobbyData = CreateLobbyData(handler.cfg, lobby)
+		})
+
+		if lobbyData != nil {
+			break
+		}
+	}
+
+	if lobbyData == nil {
+		player, lobby, err := handler.createMatchmakingLobby(preferences, playerName)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		player.SetLastKnownAddress(requestAddress)
+		SetGameplayCookies(writer, request, player, lobby)
+		state.AddLobby(lobby)
+		lobbyData = CreateLobbyData(handler.cfg, lobby)
+	}
+
+	if started, err := marshalToHTTPWriter(lobbyData, writer); err != nil {
+		if !started {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+}
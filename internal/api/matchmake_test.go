@@ -0,0 +1,101 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/scribble-rs/scribble.rs/internal/game"
+)
+
+func createMatchmakingTestLobby(t *testing.T, languageKey string, public bool) *game.Lobby {
+	t.Helper()
+
+	_, lobby, err := game.CreateLobby("", "owner", languageKey, &game.EditableLobbySettings{
+		Public:             public,
+		DrawingTime:        120,
+		Rounds:             4,
+		MaxPlayers:         4,
+		CustomWordsPerTurn: 3,
+		ClientsPerIPLimit:  1,
+		WordsPerTurn:       3,
+	}, nil, game.ChillScoring, game.ClassicMode)
+	if err != nil {
+		t.Fatalf("error creating lobby: %s", err)
+	}
+	return lobby
+}
+
+func Test_findMatchingLobbies(t *testing.T) {
+	t.Parallel()
+
+	preferences := &matchmakingPreferences{
+		languageKey: "english",
+		gameMode:    game.ClassicMode,
+	}
+
+	private := createMatchmakingTestLobby(t, "english", false)
+	german := createMatchmakingTestLobby(t, "german", true)
+	protected := createMatchmakingTestLobby(t, "english", true)
+	if err := protected.SetPassword("secret"); err != nil {
+		t.Fatal(err)
+	}
+	full := createMatchmakingTestLobby(t, "english", true)
+	for range full.MaxPlayers - 1 {
+		full.JoinPlayer("")
+	}
+	ongoing := createMatchmakingTestLobby(t, "english", true)
+	ongoing.State = game.Ongoing
+	ongoing.Round = 3
+	unstarted := createMatchmakingTestLobby(t, "english", true)
+
+	matches := findMatchingLobbies(
+		[]*game.Lobby{private, german, protected, full, ongoing, unstarted},
+		preferences, "127.0.0.1")
+	if len(matches) != 2 || matches[0] != unstarted || matches[1] != ongoing {
+		t.Errorf("expected unstarted and ongoing lobby as matches, got %v", matches)
+	}
+
+	// The address limit applies to each lobby separately.
+	unstarted.GetPlayers()[0].SetLastKnownAddress("127.0.0.1")
+	matches = findMatchingLobbies([]*game.Lobby{unstarted, ongoing}, preferences, "127.0.0.1")
+	if len(matches) != 1 || matches[0] != ongoing {
+		t.Errorf("expected only ongoing lobby as match, got %v", matches)
+	}
+}
+
+func Test_matchScorePreferences(t *testing.T) {
+	t.Parallel()
+
+	lobby := createMatchmakingTestLobby(t, "english", true)
+
+	if _, ok := matchScore(lobby, &matchmakingPreferences{
+		languageKey: "english",
+		gameMode:    game.TelephoneMode,
+	}, "127.0.0.1"); ok {
+		t.Error("lobby with different game mode mustn't match")
+	}
+
+	if _, ok := matchScore(lobby, &matchmakingPreferences{
+		languageKey:      "english",
+		gameMode:         game.ClassicMode,
+		scoreCalculation: game.CompetitiveScoring,
+	}, "127.0.0.1"); ok {
+		t.Error("lobby with different scoring mustn't match")
+	}
+
+	closeScore, ok := matchScore(lobby, &matchmakingPreferences{
+		languageKey: "english",
+		gameMode:    game.ClassicMode,
+		drawingTime: 120,
+	}, "127.0.0.1")
+	if !ok {
+		t.Fatal("lobby should match")
+	}
+	farScore, _ := matchScore(lobby, &matchmakingPreferences{
+		languageKey: "english",
+		gameMode:    game.ClassicMode,
+		drawingTime: 300,
+	}, "127.0.0.1")
+	if closeScore <= farScore {
+		t.Errorf("matching drawing time should score higher (%d <= %d)", closeScore, farScore)
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/scribble-rs/scribble.rs/internal/config"
+	"github.com/scribble-rs/scribble.rs/internal/game"
+	"github.com/scribble-rs/scribble.rs/internal/state"
+)
+
+// defaultPermanentRoomMinReadyPlayers is used for permanent rooms that don't
+// specify min_ready_players, as a single player can't play on their own.
+const defaultPermanentRoomMinReadyPlayers = "2"
+
+// createPermanentLobby creates a public lobby from the given room definition.
+// The lobby has no owner and restarts once enough players are ready.
+func createPermanentLobby(cfg *config.Config, room string) (*game.Lobby, error) {
+	overrides, err := url.ParseQuery(room)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing room definition: %w", err)
+	}
+	// Permanent rooms are meant to be found via the lobby browser.
+	overrides.Set("public", "true")
+	if !overrides.Has("min_ready_players") {
+		overrides.Set("min_ready_players", defaultPermanentRoomMinReadyPlayers)
+	}
+
+	lobby, err := createLobbyFromDefaults(cfg, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	lobby.Permanent = true
+	return lobby, nil
+}
+
+// CreatePermanentLobbies creates all permanent rooms defined in the config
+// and adds them to the state. This should only be called once at startup.
+func CreatePermanentLobbies(cfg *config.Config) error {
+	for index, room := range cfg.PermanentRooms {
+		lobby, err := createPermanentLobby(cfg, room)
+		if err != nil {
+			return fmt.Errorf("error creating permanent room %d (%s): %w", index, room, err)
+		}
+
+		state.AddLobby(lobby)
+	}
+
+	return nil
+}
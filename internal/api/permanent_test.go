@@ -0,0 +1,42 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/scribble-rs/scribble.rs/internal/config"
+)
+
+func Test_createPermanentLobby(t *testing.T) {
+	t.Parallel()
+
+	lobby, err := createPermanentLobby(&config.Default, "language=german&drawing_time=90&public=false")
+	if err != nil {
+		t.Fatalf("error creating permanent lobby: %s", err)
+	}
+	if !lobby.Permanent || !lobby.IsPublic() {
+		t.Errorf("expected permanent public lobby, got permanent=%v public=%v", lobby.Permanent, lobby.IsPublic())
+	}
+	if lobby.Wordpack != "german" || lobby.DrawingTime != 90 {
+		t.Errorf("settings weren't applied, got language %s and drawing time %d", lobby.Wordpack, lobby.DrawingTime)
+	}
+	if lobby.MinReadyPlayers != 2 {
+		t.Errorf("expected default min ready players of 2, got %d", lobby.MinReadyPlayers)
+	}
+	if len(lobby.GetPlayers()) != 0 {
+		t.Errorf("expected lobby without players, got %d players", len(lobby.GetPlayers()))
+	}
+
+	lobby, err = createPermanentLobby(&config.Default, "language=english&min_ready_players=4")
+	if err != nil {
+		t.Fatalf("error creating permanent lobby: %s", err)
+	}
+	if lobby.MinReadyPlayers != 4 {
+		t.Errorf("expected min ready players of 4, got %d", lobby.MinReadyPlayers)
+	}
+
+	for _, room := range []string{"language=klingon", "drawing_time=abc", "min_ready_players=-1", "%zz"} {
+		if _, err := createPermanentLobby(&config.Default, room); err == nil {
+			t.Errorf("expected error for room %q", room)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scribble-rs/scribble.rs/internal/game"
+	"github.com/scribble-rs/scribble.rs/internal/state"
+)
+
+// getResults returns the results of the last finished game of the lobby. By
+// default the results are returned as JSON. CSV can be requested via
+// `format=csv` or the Accept header. Results of password protected lobbies
+// are only available to players and callers that could join the lobby.
+func (handler *V1Handler) getResults(writer http.ResponseWriter, request *http.Request) {
+	if err := request.ParseForm(); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var results *game.GameResults
+	if lobby := state.GetLobby(request.PathValue("lobby_id")); lobby != nil {
+		passwordValid, err := CheckJoinPassword(lobby, request, func() bool {
+			return GetPlayer(lobby, request) != nil
+		})
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+
+		var allowed bool
+		lobby.Synchronized(func() {
+			allowed = passwordValid || GetPlayer(lobby, request) != nil ||
+				lobby.IsInviteTokenValid(request.Form.Get("invite_token"))
+			results = lobby.GameResults()
+		})
+		if !allowed {
+			http.Error(writer, "invalid password or invite token", http.StatusUnauthorized)
+			return
+		}
+	} else {
+		// Invalid sessions are treated like missing ones, as only the
+		// players of protected lobbies need one.
+		userSession, _ := GetUserSession(request)
+		results = state.GetArchivedGameResults(request.PathValue("lobby_id"), userSession)
+	}
+
+	if results == nil || time.Since(results.EndTime) >= handler.cfg.LobbyCleanup.ResultsRetention {
+		http.Error(writer, "there are no results for the requested lobby", http.StatusNotFound)
+		return
+	}
+
+	if request.URL.Query().Get("format") == "csv" ||
+		strings.Contains(request.Header.Get("Accept"), "text/csv") {
+		writeResultsCSV(writer, results)
+		return
+	}
+
+	if started, err := marshalToHTTPWriter(results, writer); err != nil {
+		if !started {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+}
+
+// resultsCSVHeader describes the columns of the CSV export. Each row is
+// either a player's final standing, a drawer of a turn or a correct guess.
+// Columns that don't apply to the row type are left empty.
+var resultsCSVHeader = []string{
+	"type", "round", "word", "player_id", "player_name",
+	"rank", "score", "points", "guess_time_ms",
+}
+
+func writeResultsCSV(writer http.ResponseWriter, results *game.GameResults) {
+	rows := [][]string{resultsCSVHeader}
+	for _, player := range results.Players {
+		rows = append(rows, []string{
+			"player", "", "", player.PlayerID.String(), player.PlayerName,
+			strconv.Itoa(player.Rank), strconv.Itoa(player.Score), "", "",
+		})
+	}
+	for _, turn := range results.Turns {
+		round := strconv.Itoa(turn.Round)
+		for _, drawer := range turn.Drawers {
+			rows = append(rows, []string{
+				"drawer", round, turn.Word, drawer.PlayerID.String(), drawer.PlayerName,
+				"", "", strconv.Itoa(drawer.Points), "",
+			})
+		}
+		for _, guess := range turn.Guesses {
+			rows = append(rows, []string{
+				"guess", round, turn.Word, guess.PlayerID.String(), guess.PlayerName,
+				"", "", strconv.Itoa(guess.Points), strconv.FormatInt(guess.GuessTime, 10),
+			})
+		}
+	}
+
+	writer.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer.Header().Set("Content-Disposition", `attachment; filename="results-`+results.LobbyID+`.csv"`)
+	// Once writing has started, we can't report errors anymore.
+	_ = csv.NewWriter(writer).WriteAll(rows)
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scribble-rs/scribble.rs/internal/config"
+	"github.com/scribble-rs/scribble.rs/internal/game"
+	"github.com/scribble-rs/scribble.rs/internal/state"
+)
+
+// createResultsTestLobby creates a lobby with two players, in which a single
+// round has been played.
+func createResultsTestLobby(t *testing.T, password string) (*game.Lobby, *game.Player) {
+	t.Helper()
+
+	settings, errs := ParseLobbySettings(&config.Default, url.Values{
+		"rounds":   {"1"},
+		"password": {password},
+	}, config.Default.LobbySettingDefaults)
+	if len(errs) != 0 {
+		t.Fatalf("error parsing lobby settings: %v", errs)
+	}
+	owner, lobby, err := game.CreateLobby("", "owner", settings.LanguageKey,
+		&settings.Editable, settings.CustomWords, settings.ScoreCalculation, settings.GameMode)
+	if err != nil {
+		t.Fatalf("error creating lobby: %s", err)
+	}
+	if err := ApplyLobbySettings(&config.Default, lobby, settings); err != nil {
+		t.Fatalf("error applying lobby settings: %s", err)
+	}
+	owner.Connected = true
+	guest := lobby.JoinPlayer("guest")
+	guest.Connected = true
+
+	handleEvent := func(eventType string, data any, player *game.Player) {
+		t.Helper()
+		payload, err := json.Marshal(map[string]any{"type": eventType, "data": data})
+		if err != nil {
+			t.Fatalf("error encoding event: %s", err)
+		}
+		if err := lobby.HandleEvent(eventType, payload, player); err != nil {
+			t.Fatalf("error handling %s event: %s", eventType, err)
+		}
+	}
+
+	handleEvent(game.EventTypeStart, nil, owner)
+	// Each player draws once and the other player guesses the word.
+	for range 2 {
+		var drawer *game.Player
+		lobby.Synchronized(func() {
+			drawer = lobby.Drawer()
+		})
+		guesser := owner
+		if drawer == owner {
+			guesser = guest
+		}
+		handleEvent(game.EventTypeChooseWord, 0, drawer)
+		var word string
+		lobby.Synchronized(func() {
+			word = lobby.CurrentWord
+		})
+		handleEvent(game.EventTypeMessage, word, guesser)
+	}
+	var results *game.GameResults
+	lobby.Synchronized(func() {
+		results = lobby.GameResults()
+	})
+	if results == nil {
+		t.Fatal("expected game to be over")
+	}
+
+	state.AddLobby(lobby)
+	return lobby, guest
+}
+
+func requestResults(
+	t *testing.T,
+	handler *V1Handler,
+	lobbyID string,
+	form url.Values,
+	player *game.Player,
+) *httptest.ResponseRecorder {
+	t.Helper()
+
+	request, err := http.NewRequest(http.MethodGet, "/?"+form.Encode(), nil)
+	if err != nil {
+		t.Fatalf("error creating request: %s", err)
+	}
+	request.RemoteAddr = "127.0.0.1:12345"
+	request.SetPathValue("lobby_id", lobbyID)
+	if player != nil {
+		request.Header.Set("Usersession", player.GetUserSession().String())
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.getResults(recorder, request)
+	return recorder
+}
+
+func Test_getResults(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(&config.Default)
+	lobby, _ := createResultsTestLobby(t, "")
+	defer state.RemoveLobby(lobby.LobbyID)
+
+	response := requestResults(t, handler, lobby.LobbyID, url.Values{}, nil)
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", response.Code, response.Body.String())
+	}
+	var results game.GameResults
+	if err := json.Unmarshal(response.Body.Bytes(), &results); err != nil {
+		t.Fatalf("error decoding results: %s", err)
+	}
+	if results.LobbyID != lobby.LobbyID || len(results.Players) != 2 || len(results.Turns) != 2 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	response = requestResults(t, handler, lobby.LobbyID, url.Values{"format": {"csv"}}, nil)
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", response.Code, response.Body.String())
+	}
+	if contentType := response.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/csv") {
+		t.Errorf("expected CSV content type, got %s", contentType)
+	}
+	rows, err := csv.NewReader(response.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("error reading CSV: %s", err)
+	}
+	// Header, two players, two drawers and two guesses.
+	if len(rows) != 7 || strings.Join(rows[0], ",") != strings.Join(resultsCSVHeader, ",") {
+		t.Errorf("unexpected CSV rows: %v", rows)
+	}
+
+	expiringConfig := config.Default
+	expiringConfig.LobbyCleanup.ResultsRetention = time.Nanosecond
+	response = requestResults(t, NewHandler(&expiringConfig), lobby.LobbyID, url.Values{}, nil)
+	if response.Code != http.StatusNotFound {
+		t.Errorf("expected expired results to be gone, got status %d", response.Code)
+	}
+}
+
+func Test_getResultsProtected(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(&config.Default)
+	lobby, guest := createResultsTestLobby(t, "secret")
+	defer state.RemoveLobby(lobby.LobbyID)
+
+	var inviteToken game.InviteToken
+	lobby.Synchronized(func() {
+		inviteToken = lobby.CreateInviteToken(time.Hour)
+	})
+
+	for _, testCase := range []struct {
+		name   string
+		form   url.Values
+		player *game.Player
+		status int
+	}{
+		{name: "outsider", form: url.Values{}, status: http.StatusUnauthorized},
+		{name: "wrong password", form: url.Values{"password": {"wrong"}}, status: http.StatusUnauthorized},
+		{name: "password", form: url.Values{"password": {"secret"}}, status: http.StatusOK},
+		{name: "invite token", form: url.Values{"invite_token": {inviteToken.Token}}, status: http.StatusOK},
+		{name: "player", form: url.Values{}, player: guest, status: http.StatusOK},
+	} {
+		response := requestResults(t, handler, lobby.LobbyID, testCase.form, testCase.player)
+		if response.Code != testCase.status {
+			t.Errorf("%s: expected status %d, got %d", testCase.name, testCase.status, response.Code)
+		}
+	}
+
+	// Once the lobby is gone, only its players can access the results.
+	state.RemoveLobby(lobby.LobbyID)
+	response := requestResults(t, handler, lobby.LobbyID, url.Values{"password": {"secret"}}, nil)
+	if response.Code != http.StatusNotFound {
+		t.Errorf("expected archived results to be hidden from outsiders, got status %d", response.Code)
+	}
+	response = requestResults(t, handler, lobby.LobbyID, url.Values{}, guest)
+	if response.Code != http.StatusOK {
+		t.Errorf("expected archived results to be available to players, got status %d", response.Code)
+	}
+}
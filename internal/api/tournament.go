@@ -0,0 +1,244 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/scribble-rs/scribble.rs/internal/game"
+	"github.com/scribble-rs/scribble.rs/internal/state"
+)
+
+// ErrTournamentNotExistent means that the tournament couldn't be found.
+var ErrTournamentNotExistent = errors.New("the requested tournament doesn't exist")
+
+// defaultTournamentMinReadyPlayers is used for tournaments that don't specify
+// min_ready_players, as tournament lobbies don't have an owner that could
+// start the game.
+const defaultTournamentMinReadyPlayers = "2"
+
+// TournamentData is returned to the organizer on creation of a tournament.
+type TournamentData struct {
+	*game.TournamentOverview
+	// OrganizerToken has to be passed as organizer_token in order to
+	// manage the tournament.
+	OrganizerToken string `json:"organizerToken"`
+}
+
+// TournamentParticipantData is returned on registration. The ID is required
+// for entering the tournament lobbies and should be kept secret.
+type TournamentParticipantData struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// postTournament creates a tournament. All tournament lobbies use the lobby
+// settings passed with this request.
+func (handler *V1Handler) postTournament(writer http.ResponseWriter, request *http.Request) {
+	if err := request.ParseForm(); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var requestErrors []string
+	finalistsPerLobby, err := ParseFinalistsPerLobby(handler.cfg, request.Form.Get("finalists_per_lobby"))
+	if err != nil {
+		requestErrors = append(requestErrors, err.Error())
+	}
+
+	// Tournament lobbies are only reachable via the tournament.
+	request.Form.Set("public", "false")
+	if !request.Form.Has("min_ready_players") {
+		request.Form.Set("min_ready_players", defaultTournamentMinReadyPlayers)
+	}
+	template, err := createLobbyFromDefaults(handler.cfg, request.Form)
+	if err != nil {
+		requestErrors = append(requestErrors, err.Error())
+	} else if template.GameMode != game.ClassicMode {
+		requestErrors = append(requestErrors, "tournaments require the classic game mode")
+	}
+
+	if len(requestErrors) != 0 {
+		http.Error(writer, strings.Join(requestErrors, ";"), http.StatusBadRequest)
+		return
+	}
+
+	tournament := game.NewTournament(template, finalistsPerLobby, handler.cfg.MaxTournamentParticipants)
+	state.AddTournament(tournament)
+
+	tournamentData := &TournamentData{
+		TournamentOverview: tournament.Overview(),
+		OrganizerToken:     tournament.OrganizerToken(),
+	}
+	if started, err := marshalToHTTPWriter(tournamentData, writer); err != nil {
+		if !started {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+}
+
+func (handler *V1Handler) getTournament(writer http.ResponseWriter, request *http.Request) {
+	tournament := state.GetTournament(request.PathValue("tournament_id"))
+	if tournament == nil {
+		http.Error(writer, ErrTournamentNotExistent.Error(), http.StatusNotFound)
+		return
+	}
+
+	if started, err := marshalToHTTPWriter(tournament.Overview(), writer); err != nil {
+		if !started {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+}
+
+// postTournamentParticipant registers a participant for the tournament.
+// Registration is only possible before the tournament has been started.
+func (handler *V1Handler) postTournamentParticipant(writer http.ResponseWriter, request *http.Request) {
+	tournament := state.GetTournament(request.PathValue("tournament_id"))
+	if tournament == nil {
+		http.Error(writer, ErrTournamentNotExistent.Error(), http.StatusNotFound)
+		return
+	}
+
+	participant, err := tournament.Register(GetPlayername(request))
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	participantData := &TournamentParticipantData{
+		ID:   participant.ID.String(),
+		Name: participant.Name,
+	}
+	if started, err := marshalToHTTPWriter(participantData, writer); err != nil {
+		if !started {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+}
+
+// postTournamentStart closes the registration and creates the tournament
+// lobbies. Only the organizer is allowed to do so.
+func (handler *V1Handler) postTournamentStart(writer http.ResponseWriter, request *http.Request) {
+	tournament := getOrganizedTournament(writer, request)
+	if tournament == nil {
+		return
+	}
+
+	lobbies, err := tournament.Start()
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, lobby := range lobbies {
+		state.AddLobby(lobby)
+	}
+
+	if started, err := marshalToHTTPWriter(tournament.Overview(), writer); err != nil {
+		if !started {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+}
+
+// postTournamentAdvance seeds the finalists without waiting for all
+// qualifier lobbies to finish. Only the organizer is allowed to do so.
+func (handler *V1Handler) postTournamentAdvance(writer http.ResponseWriter, request *http.Request) {
+	tournament := getOrganizedTournament(writer, request)
+	if tournament == nil {
+		return
+	}
+
+	if err := tournament.Advance(); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if started, err := marshalToHTTPWriter(tournament.Overview(), writer); err != nil {
+		if !started {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+}
+
+// deleteTournament removes the tournament and all of its lobbies. Only the
+// organizer is allowed to do so.
+func (handler *V1Handler) deleteTournament(writer http.ResponseWriter, request *http.Request) {
+	tournament := getOrganizedTournament(writer, request)
+	if tournament == nil {
+		return
+	}
+
+	state.RemoveTournament(tournament.ID)
+}
+
+// postTournamentEnter places the participant into the lobby they're
+// currently assigned to. Entering again after being seeded into the final
+// places the participant into the final lobby.
+func (handler *V1Handler) postTournamentEnter(writer http.ResponseWriter, request *http.Request) {
+	tournament := state.GetTournament(request.PathValue("tournament_id"))
+	if tournament == nil {
+		http.Error(writer, ErrTournamentNotExistent.Error(), http.StatusNotFound)
+		return
+	}
+
+	participantID, err := uuid.FromString(request.PathValue("participant_id"))
+	if err != nil {
+		http.Error(writer, game.ErrTournamentParticipantNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	lobby, player, err := tournament.Enter(participantID)
+	if err != nil {
+		if errors.Is(err, game.ErrTournamentParticipantNotFound) {
+			http.Error(writer, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	var lobbyData *LobbyData
+	lobby.Synchronized(func() {
+		player.SetLastKnownAddress(GetIPAddressFromRequest(request))
+		SetGameplayCookies(writer, request, player, lobby)
+		lobbyData = CreateLobbyData(handler.cfg, lobby)
+	})
+
+	if started, err := marshalToHTTPWriter(lobbyData, writer); err != nil {
+		if !started {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+}
+
+// getOrganizedTournament returns the requested tournament, if the request
+// contains the organizer token. Otherwise an error is written and nil is
+// returned.
+func getOrganizedTournament(writer http.ResponseWriter, request *http.Request) *game.Tournament {
+	tournament := state.GetTournament(request.PathValue("tournament_id"))
+	if tournament == nil {
+		http.Error(writer, ErrTournamentNotExistent.Error(), http.StatusNotFound)
+		return nil
+	}
+
+	if err := request.ParseForm(); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return nil
+	}
+
+	if !tournament.CheckOrganizerToken(request.Form.Get("organizer_token")) {
+		http.Error(writer, "only the tournament organizer can manage the tournament", http.StatusForbidden)
+		return nil
+	}
+
+	return tournament
+}
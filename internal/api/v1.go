@@ -15,11 +15,12 @@ import (
 	"github.com/scribble-rs/scribble.rs/internal/config"
 	"github.com/scribble-rs/scribble.rs/internal/game"
 	"github.com/scribble-rs/scribble.rs/internal/state"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 )
 
-var ErrLobbyNotExistent = errors.New("the requested lobby doesn't exist")
+var (
+	ErrLobbyNotExistent        = errors.New("the requested lobby doesn't exist")
+	ErrTooManyPasswordAttempts = errors.New("too many password attempts, please try again later")
+)
 
 type V1Handler struct {
 	cfg *config.Config
@@ -47,17 +48,21 @@ type LobbyEntries []*LobbyEntry
 
 // LobbyEntry is an API object for representing a join-able public lobby.
 type LobbyEntry struct {
-	LobbyID         string     `json:"lobbyId"`
-	Wordpack        string     `json:"wordpack"`
-	Scoring         string     `json:"scoring"`
-	State           game.State `json:"state"`
-	PlayerCount     int        `json:"playerCount"`
-	MaxPlayers      int        `json:"maxPlayers"`
-	Round           int        `json:"round"`
-	Rounds          int        `json:"rounds"`
-	DrawingTime     int        `json:"drawingTime"`
-	MaxClientsPerIP int        `json:"maxClientsPerIp"`
-	CustomWords     bool       `json:"customWords"`
+	LobbyID         string        `json:"lobbyId"`
+	LobbyCode       string        `json:"lobbyCode"`
+	Wordpack        string        `json:"wordpack"`
+	Scoring         string        `json:"scoring"`
+	GameMode        game.GameMode `json:"gameMode"`
+	State           game.State    `json:"state"`
+	PlayerCount     int           `json:"playerCount"`
+	MaxPlayers      int           `json:"maxPlayers"`
+	Round           int           `json:"round"`
+	Rounds          int           `json:"rounds"`
+	DrawingTime     int           `json:"drawingTime"`
+	MaxClientsPerIP int           `json:"maxClientsPerIp"`
+	CustomWords     bool          `json:"customWords"`
+	HasPassword     bool          `json:"hasPassword"`
+	Permanent       bool          `json:"permanent"`
 }
 
 func (handler *V1Handler) getLobbies(writer http.ResponseWriter, _ *http.Request) {
@@ -71,6 +76,7 @@ func (handler *V1Handler) getLobbies(writer http.ResponseWriter, _ *http.Request
 		// important to get 100% consistent results here.
 		lobbyEntries = append(lobbyEntries, &LobbyEntry{
 			LobbyID:         lobby.LobbyID,
+			LobbyCode:       lobby.LobbyCode,
 			PlayerCount:     lobby.GetConnectedPlayerCount(),
 			MaxPlayers:      lobby.MaxPlayers,
 			Round:           lobby.Round,
@@ -81,6 +87,9 @@ func (handler *V1Handler) getLobbies(writer http.ResponseWriter, _ *http.Request
 			Wordpack:        lobby.Wordpack,
 			State:           lobby.State,
 			Scoring:         lobby.ScoreCalculation.Identifier(),
+			GameMode:        lobby.GameMode,
+			HasPassword:     lobby.HasPassword(),
+			Permanent:       lobby.Permanent,
 		})
 	}
 
@@ -113,64 +122,8 @@ func (handler *V1Handler) postLobby(writer http.ResponseWriter, request *http.Re
 		}
 	}
 
-	scoreCalculation, scoreCalculationInvalid := ParseScoreCalculation(request.Form.Get("score_calculation"))
-	languageRawValue := strings.ToLower(strings.TrimSpace(request.Form.Get("language")))
-	languageData, languageKey, languageInvalid := ParseLanguage(languageRawValue)
-	drawingTime, drawingTimeInvalid := ParseDrawingTime(handler.cfg, request.Form.Get("drawing_time"))
-	rounds, roundsInvalid := ParseRounds(handler.cfg, request.Form.Get("rounds"))
-	maxPlayers, maxPlayersInvalid := ParseMaxPlayers(handler.cfg, request.Form.Get("max_players"))
-	customWordsPerTurn, customWordsPerTurnInvalid := ParseCustomWordsPerTurn(handler.cfg, request.Form.Get("custom_words_per_turn"))
-	clientsPerIPLimit, clientsPerIPLimitInvalid := ParseClientsPerIPLimit(handler.cfg, request.Form.Get("clients_per_ip_limit"))
-	publicLobby, publicLobbyInvalid := ParseBoolean("public", request.Form.Get("public"))
-	wordsPerTurn, wordsPerTurnInvalid := ParseWordsPerTurn(handler.cfg, request.Form.Get("words_per_turn"))
-
-	if wordsPerTurn < customWordsPerTurn {
-		wordsPerTurnInvalid = errors.New("words per turn must be greater than or equal to custom words per turn")
-	}
-
-	var lowercaser cases.Caser
-	if languageInvalid != nil {
-		lowercaser = cases.Lower(language.English)
-	} else {
-		lowercaser = languageData.Lowercaser()
-	}
-
-	customWords, customWordsInvalid := ParseCustomWords(lowercaser, request.Form.Get("custom_words"))
-
-	if scoreCalculationInvalid != nil {
-		requestErrors = append(requestErrors, scoreCalculationInvalid.Error())
-	}
-	if languageInvalid != nil {
-		requestErrors = append(requestErrors, languageInvalid.Error())
-	}
-	if drawingTimeInvalid != nil {
-		requestErrors = append(requestErrors, drawingTimeInvalid.Error())
-	}
-	if roundsInvalid != nil {
-		requestErrors = append(requestErrors, roundsInvalid.Error())
-	}
-	if maxPlayersInvalid != nil {
-		requestErrors = append(requestErrors, maxPlayersInvalid.Error())
-	}
-	if customWordsInvalid != nil {
-		requestErrors = append(requestErrors, customWordsInvalid.Error())
-	}
-	if customWordsPerTurnInvalid != nil {
-		requestErrors = append(requestErrors, customWordsPerTurnInvalid.Error())
-	} else {
-		if languageRawValue == "custom" && len(customWords) == 0 {
-			requestErrors = append(requestErrors, "custom words must be provided when using custom language")
-		}
-	}
-	if clientsPerIPLimitInvalid != nil {
-		requestErrors = append(requestErrors, clientsPerIPLimitInvalid.Error())
-	}
-	if publicLobbyInvalid != nil {
-		requestErrors = append(requestErrors, publicLobbyInvalid.Error())
-	}
-	if wordsPerTurnInvalid != nil {
-		requestErrors = append(requestErrors, wordsPerTurnInvalid.Error())
-	}
+	settings, settingsErrors := ParseLobbySettings(handler.cfg, request.Form, config.LobbySettingDefaults{})
+	requestErrors = append(requestErrors, settingsErrors...)
 
 	if len(requestErrors) != 0 {
 		http.Error(writer, strings.Join(requestErrors, ";"), http.StatusBadRequest)
@@ -178,17 +131,8 @@ func (handler *V1Handler) postLobby(writer http.ResponseWriter, request *http.Re
 	}
 
 	playerName := GetPlayername(request)
-	lobbySettings := &game.EditableLobbySettings{
-		Rounds:             rounds,
-		DrawingTime:        drawingTime,
-		MaxPlayers:         maxPlayers,
-		CustomWordsPerTurn: customWordsPerTurn,
-		ClientsPerIPLimit:  clientsPerIPLimit,
-		Public:             publicLobby,
-		WordsPerTurn:       wordsPerTurn,
-	}
-	player, lobby, err := game.CreateLobby(lobbyId, playerName,
-		languageKey, lobbySettings, customWords, scoreCalculation)
+	player, lobby, err := game.CreateLobby(lobbyId, playerName, settings.LanguageKey,
+		&settings.Editable, settings.CustomWords, settings.ScoreCalculation, settings.GameMode)
 	if err != nil {
 		http.Error(writer, err.Error(), http.StatusBadRequest)
 		return
@@ -203,12 +147,18 @@ func (handler *V1Handler) postLobby(writer http.ResponseWriter, request *http.Re
 		return
 	}
 
-	lobby.WriteObject = WriteObject
-	lobby.WritePreparedMessage = WritePreparedMessage
+	if err := ApplyLobbySettings(handler.cfg, lobby, settings); err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	player.SetLastKnownAddress(GetIPAddressFromRequest(request))
 
 	SetGameplayCookies(writer, request, player, lobby)
 
+	// We only add the lobby if everything else was successful. This has to
+	// happen before creating the lobby data, as it assigns the lobby code.
+	state.AddLobby(lobby)
+
 	lobbyData := CreateLobbyData(handler.cfg, lobby)
 
 	if started, err := marshalToHTTPWriter(lobbyData, writer); err != nil {
@@ -217,9 +167,6 @@ func (handler *V1Handler) postLobby(writer http.ResponseWriter, request *http.Re
 		}
 		return
 	}
-
-	// We only add the lobby if everything else was successful.
-	state.AddLobby(lobby)
 }
 
 func (handler *V1Handler) postPlayer(writer http.ResponseWriter, request *http.Request) {
@@ -229,12 +176,30 @@ func (handler *V1Handler) postPlayer(writer http.ResponseWriter, request *http.R
 		return
 	}
 
+	if err := request.ParseForm(); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	passwordValid, err := CheckJoinPassword(lobby, request, func() bool {
+		return GetPlayer(lobby, request) != nil
+	})
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
 	var lobbyData *LobbyData
 
 	lobby.Synchronized(func() {
 		player := GetPlayer(lobby, request)
 
 		if player == nil {
+			if !passwordValid && !lobby.IsInviteTokenValid(request.Form.Get("invite_token")) {
+				http.Error(writer, "invalid password or invite token", http.StatusUnauthorized)
+				return
+			}
+
 			if !lobby.HasFreePlayerSlot() {
 				http.Error(writer, "lobby already full", http.StatusUnauthorized)
 				return
@@ -269,6 +234,157 @@ func (handler *V1Handler) postPlayer(writer http.ResponseWriter, request *http.R
 	}
 }
 
+// postInvite creates a new invite token for the lobby. Only the owner is
+// allowed to do so.
+func (handler *V1Handler) postInvite(writer http.ResponseWriter, request *http.Request) {
+	lobby := state.GetLobby(GetLobbyId(request))
+	if lobby == nil {
+		http.Error(writer, ErrLobbyNotExistent.Error(), http.StatusNotFound)
+		return
+	}
+
+	if err := request.ParseForm(); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	validity, err := ParseInviteTokenValidity(handler.cfg, request.Form.Get("validity"))
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var inviteToken *game.InviteToken
+	lobby.Synchronized(func() {
+		if !isLobbyOwner(lobby, request) {
+			http.Error(writer, "only the lobby owner can create invites", http.StatusForbidden)
+			return
+		}
+
+		token := lobby.CreateInviteToken(validity)
+		inviteToken = &token
+	})
+
+	if inviteToken != nil {
+		if started, err := marshalToHTTPWriter(inviteToken, writer); err != nil {
+			if !started {
+				http.Error(writer, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+	}
+}
+
+// deleteInvite revokes an invite token of the lobby. Players that have
+// already joined via the token stay in the lobby.
+func (handler *V1Handler) deleteInvite(writer http.ResponseWriter, request *http.Request) {
+	lobby := state.GetLobby(GetLobbyId(request))
+	if lobby == nil {
+		http.Error(writer, ErrLobbyNotExistent.Error(), http.StatusNotFound)
+		return
+	}
+
+	lobby.Synchronized(func() {
+		if !isLobbyOwner(lobby, request) {
+			http.Error(writer, "only the lobby owner can revoke invites", http.StatusForbidden)
+			return
+		}
+
+		if !lobby.RevokeInviteToken(request.PathValue("token")) {
+			http.Error(writer, "the invite token doesn't exist", http.StatusNotFound)
+		}
+	})
+}
+
+// postClone creates a new lobby with the same settings and custom words as
+// the given lobby. Only the owner is allowed to do this and becomes the owner
+// of the new lobby. All other players are invited to join the new lobby.
+func (handler *V1Handler) postClone(writer http.ResponseWriter, request *http.Request) {
+	lobby := state.GetLobby(GetLobbyId(request))
+	if lobby == nil {
+		http.Error(writer, ErrLobbyNotExistent.Error(), http.StatusNotFound)
+		return
+	}
+
+	var (
+		clone       *game.Lobby
+		player      *game.Player
+		inviteToken string
+	)
+	lobby.Synchronized(func() {
+		if !isLobbyOwner(lobby, request) {
+			http.Error(writer, "only the lobby owner can clone the lobby", http.StatusForbidden)
+			return
+		}
+
+		clone = lobby.Clone()
+		player = clone.JoinPlayer(lobby.GetOwner().Name)
+		clone.OwnerID = player.ID
+		if clone.HasPassword() {
+			inviteToken = clone.CreateInviteToken(handler.cfg.InviteTokenValidity).Token
+		}
+	})
+	if clone == nil {
+		return
+	}
+
+	player.SetLastKnownAddress(GetIPAddressFromRequest(request))
+	SetGameplayCookies(writer, request, player, clone)
+
+	// The state mustn't be accessed while holding the lobby lock, as the
+	// state locks lobbies itself. Adding the lobby assigns the lobby code.
+	state.AddLobby(clone)
+
+	lobby.Synchronized(func() {
+		lobby.Broadcast(&game.Event{
+			Type: game.EventTypeLobbyCloned,
+			Data: game.LobbyClonedEvent{
+				LobbyID:     clone.LobbyID,
+				LobbyCode:   clone.LobbyCode,
+				InviteToken: inviteToken,
+			},
+		})
+	})
+
+	lobbyData := CreateLobbyData(handler.cfg, clone)
+	if started, err := marshalToHTTPWriter(lobbyData, writer); err != nil {
+		if !started {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+}
+
+// CheckJoinPassword checks the password of a request that might join the
+// lobby. The form has to be parsed already. Hashing is slow on purpose, so
+// the password is only checked if the caller isn't a player yet and has no
+// valid invite token. If the check is skipped, false is returned, unless the
+// lobby has no password. The number of password attempts is limited per IP.
+func CheckJoinPassword(lobby *game.Lobby, request *http.Request, isPlayer func() bool) (bool, error) {
+	password := request.Form.Get("password")
+
+	var checkPassword, allowed bool
+	lobby.Synchronized(func() {
+		checkPassword = lobby.HasPassword() && password != "" && !isPlayer() &&
+			!lobby.IsInviteTokenValid(request.Form.Get("invite_token"))
+		allowed = !checkPassword || lobby.AllowPasswordAttempt(GetIPAddressFromRequest(request))
+	})
+	if !allowed {
+		return false, ErrTooManyPasswordAttempts
+	}
+
+	if !lobby.HasPassword() {
+		return true, nil
+	}
+	// Hashing doesn't require the lock and would block the lobby otherwise.
+	return checkPassword && lobby.CheckPassword(password), nil
+}
+
+func isLobbyOwner(lobby *game.Lobby, request *http.Request) bool {
+	owner := lobby.GetOwner()
+	return owner != nil && owner == GetPlayer(lobby, request)
+}
+
 // SetGameplayCookies takes the players usersession and lobby id
 // and sets them as a cookie.
 func SetGameplayCookies(
@@ -412,38 +528,51 @@ func (handler *V1Handler) getStats(writer http.ResponseWriter, _ *http.Request)
 }
 
 // SuggestedBrushSizes is suggested brush sizes value used for
-// Lobbydata objects. A unit test makes sure these values are ordered
-// and within the specified bounds.
+// Lobbydata objects of lobbies using the default brush sizes. A unit test
+// makes sure these values are ordered and within the specified bounds.
 var SuggestedBrushSizes = [4]uint8{8, 16, 24, 32}
 
-// GameConstants are values that are lobby-independent and can't be changed via
-// settings, neither at compile time nor at startup time.
+// GameConstants are values that can't be changed during the lifetime of a
+// lobby. GameConstantsData contains the values used by lobbies that don't
+// define their own CanvasSettings.
 type GameConstants struct {
+	// AspectRatio is the aspect ratio of the canvas, such as "16:9".
+	AspectRatio string `json:"aspectRatio"`
 	// DrawingBoardBaseWidth is the internal canvas width and is needed for
-	// correctly up- / downscaling drawing instructions. Deprecated, but kept for compat.
+	// correctly up- / downscaling drawing instructions.
 	DrawingBoardBaseWidth uint16 `json:"drawingBoardBaseWidth"`
 	// DrawingBoardBaseHeight is the internal canvas height and is needed for
-	// correctly up- / downscaling drawing instructions. Deprecated, but kept for compat.
+	// correctly up- / downscaling drawing instructions.
 	DrawingBoardBaseHeight uint16 `json:"drawingBoardBaseHeight"`
 	// MinBrushSize is the minimum amount of pixels the brush can draw in.
 	MinBrushSize uint8 `json:"minBrushSize"`
 	// MaxBrushSize is the maximum amount of pixels the brush can draw in.
 	MaxBrushSize uint8 `json:"maxBrushSize"`
-	// CanvasColor is the initially (empty) color of the canvas.
+	// CanvasColor is the index of the initial (empty) color of the canvas.
+	// This also applies to custom palettes.
 	CanvasColor uint8 `json:"canvasColor"`
 	// SuggestedBrushSizes are suggestions for the different brush sizes
 	// that the user can choose between. These brushes are guaranteed to
 	// be ordered from low to high and stay with the bounds.
 	SuggestedBrushSizes [4]uint8 `json:"suggestedBrushSizes"`
+	// DrawingTools are the draw event types supported by the server.
+	// Clients should hide tools that aren't part of this list.
+	DrawingTools []string `json:"drawingTools"`
+	// MaxTelephoneTextLength is the maximum amount of characters of prompts
+	// and descriptions in telephone lobbies.
+	MaxTelephoneTextLength int `json:"maxTelephoneTextLength"`
 }
 
 var GameConstantsData = &GameConstants{
+	AspectRatio:            game.DefaultAspectRatio,
 	DrawingBoardBaseWidth:  game.DrawingBoardBaseWidth,
 	DrawingBoardBaseHeight: game.DrawingBoardBaseHeight,
 	MinBrushSize:           game.MinBrushSize,
 	MaxBrushSize:           game.MaxBrushSize,
 	CanvasColor:            0, /* White */
 	SuggestedBrushSizes:    SuggestedBrushSizes,
+	DrawingTools:           game.DrawingTools,
+	MaxTelephoneTextLength: game.MaxTelephoneTextLength,
 }
 
 // LobbyData is the data necessary for correctly configuring a lobby.
@@ -454,6 +583,12 @@ type LobbyData struct {
 	game.EditableLobbySettings
 	*GameConstants
 	IsWordpackRtl bool
+	GameMode      game.GameMode `json:"gameMode"`
+	// LobbyCode is a short alternative to the lobby ID, that can be used
+	// anywhere a lobby ID is accepted.
+	LobbyCode string `json:"lobbyCode"`
+	// Palette is only set if the lobby uses a custom palette.
+	Palette []string `json:"palette,omitempty"`
 }
 
 // CreateLobbyData creates a ready to use LobbyData object containing data
@@ -462,8 +597,32 @@ func CreateLobbyData(cfg *config.Config, lobby *game.Lobby) *LobbyData {
 	return &LobbyData{
 		SettingBounds:         cfg.LobbySettingBounds,
 		EditableLobbySettings: lobby.EditableLobbySettings,
-		GameConstants:         GameConstantsData,
+		GameConstants:         createGameConstants(lobby),
 		IsWordpackRtl:         lobby.IsWordpackRtl,
+		GameMode:              lobby.GameMode,
+		LobbyCode:             lobby.LobbyCode,
+		Palette:               lobby.Palette,
+	}
+}
+
+func createGameConstants(lobby *game.Lobby) *GameConstants {
+	size := lobby.DrawingBoardSize()
+	minBrushSize, maxBrushSize := lobby.BrushSizeBounds()
+	aspectRatio := lobby.AspectRatio
+	if aspectRatio == "" {
+		aspectRatio = game.DefaultAspectRatio
+	}
+
+	return &GameConstants{
+		AspectRatio:            aspectRatio,
+		DrawingBoardBaseWidth:  uint16(size.Width),
+		DrawingBoardBaseHeight: uint16(size.Height),
+		MinBrushSize:           minBrushSize,
+		MaxBrushSize:           maxBrushSize,
+		CanvasColor:            GameConstantsData.CanvasColor,
+		SuggestedBrushSizes:    lobby.SuggestedBrushSizes(),
+		DrawingTools:           GameConstantsData.DrawingTools,
+		MaxTelephoneTextLength: GameConstantsData.MaxTelephoneTextLength,
 	}
 }
 
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/scribble-rs/scribble.rs/internal/game"
+)
+
+func Test_checkJoinPassword(t *testing.T) {
+	t.Parallel()
+
+	lobby := game.NewLobby("", "english", &game.EditableLobbySettings{
+		DrawingTime:  120,
+		Rounds:       4,
+		MaxPlayers:   4,
+		WordsPerTurn: 3,
+	}, nil, game.ChillScoring, game.ClassicMode)
+	if err := lobby.SetPassword("secret"); err != nil {
+		t.Fatalf("error setting password: %s", err)
+	}
+	inviteToken := lobby.CreateInviteToken(time.Hour)
+
+	check := func(form url.Values, isPlayer bool) (bool, error) {
+		t.Helper()
+		request, err := http.NewRequest(http.MethodGet, "/?"+form.Encode(), nil)
+		if err != nil {
+			t.Fatalf("error creating request: %s", err)
+		}
+		request.RemoteAddr = "127.0.0.1:12345"
+		if err := request.ParseForm(); err != nil {
+			t.Fatalf("error parsing form: %s", err)
+		}
+		return CheckJoinPassword(lobby, request, func() bool { return isPlayer })
+	}
+
+	if valid, err := check(url.Values{"password": {"secret"}}, false); !valid || err != nil {
+		t.Errorf("expected correct password to be valid, got %v, %v", valid, err)
+	}
+	// Players and invitees don't need the password, so it isn't hashed.
+	if valid, err := check(url.Values{"password": {"secret"}}, true); valid || err != nil {
+		t.Errorf("expected password check to be skipped for players, got %v, %v", valid, err)
+	}
+	if valid, err := check(url.Values{
+		"password":     {"secret"},
+		"invite_token": {inviteToken.Token},
+	}, false); valid || err != nil {
+		t.Errorf("expected password check to be skipped for invitees, got %v, %v", valid, err)
+	}
+
+	// The correct password counted as an attempt as well.
+	for range 4 {
+		if valid, err := check(url.Values{"password": {"wrong"}}, false); valid || err != nil {
+			t.Errorf("expected wrong password to be invalid, got %v, %v", valid, err)
+		}
+	}
+	if _, err := check(url.Values{"password": {"secret"}}, false); !errors.Is(err, ErrTooManyPasswordAttempts) {
+		t.Errorf("expected too many password attempts, got %v", err)
+	}
+	// Skipped checks aren't affected by the limit.
+	if _, err := check(url.Values{"password": {"secret"}}, true); err != nil {
+		t.Errorf("expected players to bypass the limit, got %v", err)
+	}
+}
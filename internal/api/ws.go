@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/gofrs/uuid/v5"
@@ -25,8 +26,34 @@ var (
 		ParallelEnabled:   true,
 		PermessageDeflate: gws.PermessageDeflate{Enabled: true},
 	})
+	// binaryUpgrader is used for clients that opt into the binary format for
+	// drawing events. gws fails the handshake if none of the configured
+	// subprotocols is requested, so we can't use a single upgrader for both.
+	binaryUpgrader = gws.NewUpgrader(&socketHandler{}, &gws.ServerOption{
+		Recovery:          gws.Recovery,
+		ParallelEnabled:   true,
+		PermessageDeflate: gws.PermessageDeflate{Enabled: true},
+		SubProtocols:      []string{BinarySubProtocol},
+	})
 )
 
+// BinarySubProtocol is the websocket subprotocol clients have to request in
+// order to send and receive drawing events in the binary format.
+const BinarySubProtocol = "binary.scribble.rs"
+
+// requestsBinarySubProtocol checks whether the client listed the binary
+// subprotocol in the Sec-WebSocket-Protocol header.
+func requestsBinarySubProtocol(request *http.Request) bool {
+	for _, header := range request.Header.Values("Sec-WebSocket-Protocol") {
+		for protocol := range strings.SplitSeq(header, ",") {
+			if strings.TrimSpace(protocol) == BinarySubProtocol {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (handler *V1Handler) websocketUpgrade(writer http.ResponseWriter, request *http.Request) {
 	userSession, err := GetUserSession(request)
 	if err != nil {
@@ -62,7 +89,12 @@ func (handler *V1Handler) websocketUpgrade(writer http.ResponseWriter, request *
 			return
 		}
 
-		socket, err := upgrader.Upgrade(writer, request)
+		socketUpgrader := upgrader
+		if requestsBinarySubProtocol(request) {
+			socketUpgrader = binaryUpgrader
+		}
+
+		socket, err := socketUpgrader.Upgrade(writer, request)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusInternalServerError)
 			return
@@ -71,6 +103,7 @@ func (handler *V1Handler) websocketUpgrade(writer http.ResponseWriter, request *
 		metrics.TrackPlayerConnect()
 
 		player.SetWebsocket(socket)
+		player.SetBinaryEncoding(socket.SubProtocol() == BinarySubProtocol)
 		socket.Session().Store("player", player)
 		socket.Session().Store("lobby", lobby)
 		lobby.OnPlayerConnectUnsynchronized(player)
@@ -141,10 +174,10 @@ func (c *socketHandler) OnMessage(socket *gws.Conn, message *gws.Message) {
 	}
 
 	bytes := message.Bytes()
-	handleIncommingEvent(lobby, player, bytes)
+	handleIncommingEvent(lobby, player, message.Opcode, bytes)
 }
 
-func handleIncommingEvent(lobby *game.Lobby, player *game.Player, data []byte) {
+func handleIncommingEvent(lobby *game.Lobby, player *game.Player, opcode gws.Opcode, data []byte) {
 	defer func() {
 		if err := recover(); err != nil {
 			log.Printf("Error occurred in incomming event listener.\n\tError: %s\n\tPlayer: %s(%s)\nStack %s\n", err, player.Name, player.ID, string(debug.Stack()))
@@ -152,6 +185,15 @@ func handleIncommingEvent(lobby *game.Lobby, player *game.Player, data []byte) {
 		}
 	}()
 
+	// Binary frames are only used for drawing events, which are handled
+	// separately, since they don't contain a JSON type field.
+	if opcode == gws.OpcodeBinary {
+		if err := lobby.HandleBinaryEvent(data, player); err != nil {
+			log.Printf("Error handling binary event: %s\n", err)
+		}
+		return
+	}
+
 	var event game.EventTypeOnly
 	if err := json.Unmarshal(data, &event); err != nil {
 		log.Printf("Error unmarshalling message: %s\n", err)
@@ -176,14 +218,14 @@ func WriteObject(player *game.Player, object any) error {
 		return ErrPlayerNotConnected
 	}
 
-	bytes, err := json.Marshal(object)
+	opcode, bytes, err := encodeObject(player, object)
 	if err != nil {
-		return fmt.Errorf("error marshalling payload: %w", err)
+		return err
 	}
 
 	// We write async, as broadcast always uses the queue. If we use write, the
 	// order will become messed up, potentially causing issues in the frontend.
-	socket.WriteAsync(gws.OpcodeText, bytes, func(err error) {
+	socket.WriteAsync(opcode, bytes, func(err error) {
 		if err != nil {
 			log.Println("Error responding to player:", err.Error())
 		}
@@ -191,6 +233,22 @@ func WriteObject(player *game.Player, object any) error {
 	return nil
 }
 
+// encodeObject uses the binary format for drawing events if the player has
+// negotiated it and falls back to JSON otherwise.
+func encodeObject(player *game.Player, object any) (gws.Opcode, []byte, error) {
+	if player.UsesBinaryEncoding() {
+		if bytes, ok := game.EncodeBinaryEvent(object); ok {
+			return gws.OpcodeBinary, bytes, nil
+		}
+	}
+
+	bytes, err := json.Marshal(object)
+	if err != nil {
+		return gws.OpcodeText, nil, fmt.Errorf("error marshalling payload: %w", err)
+	}
+	return gws.OpcodeText, bytes, nil
+}
+
 func WritePreparedMessage(player *game.Player, message *gws.Broadcaster) error {
 	socket := player.GetWebsocket()
 	if socket == nil || !player.Connected {
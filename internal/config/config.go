@@ -24,7 +24,21 @@ type LobbySettingDefaults struct {
 	ClientsPerIPLimit  string `env:"CLIENTS_PER_IP_LIMIT"`
 	Language           string `env:"LANGUAGE"`
 	ScoreCalculation   string `env:"SCORE_CALCULATION"`
+	GameMode           string `env:"GAME_MODE"`
 	WordsPerTurn       string `env:"WORDS_PER_TURN"`
+	AspectRatio        string `env:"ASPECT_RATIO"`
+	MinBrushSize       string `env:"MIN_BRUSH_SIZE"`
+	MaxBrushSize       string `env:"MAX_BRUSH_SIZE"`
+	DrawersPerTurn     string `env:"DRAWERS_PER_TURN"`
+	DrawerScore        string `env:"DRAWER_SCORE"`
+	GuessTimeCap       string `env:"GUESS_TIME_CAP"`
+	GuessedPercentage  string `env:"GUESSED_PERCENTAGE"`
+	WordRerolls        string `env:"WORD_REROLLS"`
+	DrawerIdleTimeout  string `env:"DRAWER_IDLE_TIMEOUT"`
+	IdleTurns          string `env:"IDLE_TURNS"`
+	DrawOrder          string `env:"DRAW_ORDER"`
+	MinReadyPlayers    string `env:"MIN_READY_PLAYERS"`
+	StartCountdown     string `env:"START_COUNTDOWN"`
 }
 
 type CORS struct {
@@ -40,6 +54,9 @@ type LobbyCleanup struct {
 	// inactivity and won't keep the lobby up. Note that cleaning up a lobby can
 	// therefore take up to Interval + PlayerInactivityThreshold.
 	PlayerInactivityThreshold time.Duration `env:"PLAYER_INACTIVITY_THRESHOLD"`
+	// ResultsRetention is the time for which the results of a finished
+	// game can be requested, even if the lobby has been cleaned up already.
+	ResultsRetention time.Duration `env:"RESULTS_RETENTION"`
 }
 
 type Config struct {
@@ -74,6 +91,24 @@ type Config struct {
 	Port                 uint16               `env:"PORT"`
 	CORS                 CORS                 `envPrefix:"CORS_"`
 	LobbyCleanup         LobbyCleanup         `envPrefix:"LOBBY_CLEANUP_"`
+	// DrawingBatchInterval is the time for which outgoing drawing events are
+	// buffered, before being sent to the players as a single batch. If set
+	// to `0`, each drawing event is sent separately.
+	DrawingBatchInterval time.Duration `env:"DRAWING_BATCH_INTERVAL"`
+	// InviteTokenValidity is the default and maximum amount of time an
+	// invite token for a password protected lobby is valid for.
+	InviteTokenValidity time.Duration `env:"INVITE_TOKEN_VALIDITY"`
+	// PermanentRooms are public lobbies created by the server at startup.
+	// Each room is defined like the query of a lobby creation request, for
+	// example `language=english&drawing_time=90&rounds=3`.
+	// Unspecified settings fall back to LobbySettingDefaults.
+	PermanentRooms []string `env:"PERMANENT_ROOMS" envSeparator:";"`
+	// MaxScheduleDelay limits how far ahead of time a lobby can be
+	// scheduled to start.
+	MaxScheduleDelay time.Duration `env:"MAX_SCHEDULE_DELAY"`
+	// MaxTournamentParticipants limits the amount of participants that can
+	// register for a single tournament.
+	MaxTournamentParticipants int `env:"MAX_TOURNAMENT_PARTICIPANTS"`
 }
 
 var Default = Config{
@@ -87,7 +122,21 @@ var Default = Config{
 		ClientsPerIPLimit:  "2",
 		Language:           "english",
 		ScoreCalculation:   "chill",
+		GameMode:           "classic",
 		WordsPerTurn:       "3",
+		AspectRatio:        game.DefaultAspectRatio,
+		MinBrushSize:       "8",
+		MaxBrushSize:       "32",
+		DrawersPerTurn:     "1",
+		DrawerScore:        "duplicate",
+		GuessTimeCap:       "0",
+		GuessedPercentage:  "0",
+		WordRerolls:        "1",
+		DrawerIdleTimeout:  "0",
+		IdleTurns:          "0",
+		DrawOrder:          string(game.JoinDrawOrder),
+		MinReadyPlayers:    "0",
+		StartCountdown:     "0",
 	},
 	LobbySettingBounds: game.SettingBounds{
 		MinDrawingTime:        60,
@@ -101,6 +150,12 @@ var Default = Config{
 		MinCustomWordsPerTurn: 1,
 		MaxWordsPerTurn:       6,
 		MinWordsPerTurn:       1,
+		MinMinBrushSize:       4,
+		MaxMaxBrushSize:       64,
+		MaxDrawersPerTurn:     2,
+		MaxWordRerollsPerTurn: 3,
+		MaxIdleTurns:          10,
+		MaxStartCountdown:     60,
 	},
 	CORS: CORS{
 		AllowedOrigins:   []string{"*"},
@@ -109,7 +164,13 @@ var Default = Config{
 	LobbyCleanup: LobbyCleanup{
 		Interval:                  90 * time.Second,
 		PlayerInactivityThreshold: 75 * time.Second,
+		ResultsRetention:          24 * time.Hour,
 	},
+	DrawingBatchInterval: 16 * time.Millisecond,
+	InviteTokenValidity:  24 * time.Hour,
+	MaxScheduleDelay:     14 * 24 * time.Hour,
+	// Enough to fill 10 lobbies of the maximum size.
+	MaxTournamentParticipants: 240,
 }
 
 // Load loads the configuration from the environment. If a .env file is
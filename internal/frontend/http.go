@@ -154,6 +154,8 @@ func (handler *SSRHandler) SetupRoutes(register func(string, string, http.Handle
 	registerWithCsp("GET", path.Join(handler.cfg.RootPath, "lobby.js"), handler.lobbyJs)
 	registerWithCsp("GET", path.Join(handler.cfg.RootPath, "index.js"), handler.indexJs)
 	registerWithCsp("GET", path.Join(handler.cfg.RootPath, "lobby", "{lobby_id}"), handler.ssrEnterLobby)
+	// Used for submitting the lobby password, so it doesn't end up in the URL.
+	registerWithCsp("POST", path.Join(handler.cfg.RootPath, "lobby", "{lobby_id}"), handler.ssrEnterLobby)
 	registerWithCsp("POST", path.Join(handler.cfg.RootPath, "lobby"), handler.ssrCreateLobby)
 }
 
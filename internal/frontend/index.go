@@ -4,7 +4,6 @@ import (
 	//nolint:gosec //We just use this for cache busting, so it's secure enough
 
 	"crypto/md5"
-	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -16,8 +15,6 @@ import (
 	"github.com/scribble-rs/scribble.rs/internal/state"
 	"github.com/scribble-rs/scribble.rs/internal/translations"
 	"github.com/scribble-rs/scribble.rs/internal/version"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 
 	_ "embed"
 )
@@ -146,6 +143,10 @@ func (handler *SSRHandler) createDefaultIndexPageData() *IndexPageData {
 		SettingBounds:        handler.cfg.LobbySettingBounds,
 		Languages:            game.SupportedLanguages,
 		ScoreCalculations:    game.SupportedScoreCalculations,
+		GameModes:            game.SupportedGameModes,
+		DrawOrders:           game.SupportedDrawOrders,
+		AspectRatios:         game.SupportedAspectRatios,
+		MaxPasswordLength:    game.MaxPasswordLength,
 		LobbySettingDefaults: handler.cfg.LobbySettingDefaults,
 	}
 }
@@ -161,6 +162,10 @@ type IndexPageData struct {
 	Errors            []string
 	Languages         map[string]string
 	ScoreCalculations []string
+	GameModes         []string
+	DrawOrders        []string
+	AspectRatios      []string
+	MaxPasswordLength int
 }
 
 // ssrCreateLobby allows creating a lobby, optionally returning errors that
@@ -171,28 +176,7 @@ func (handler *SSRHandler) ssrCreateLobby(writer http.ResponseWriter, request *h
 		return
 	}
 
-	scoreCalculation, scoreCalculationInvalid := api.ParseScoreCalculation(request.Form.Get("score_calculation"))
-	languageRawValue := request.Form.Get("language")
-	languageData, languageKey, languageInvalid := api.ParseLanguage(languageRawValue)
-	drawingTime, drawingTimeInvalid := api.ParseDrawingTime(handler.cfg, request.Form.Get("drawing_time"))
-	rounds, roundsInvalid := api.ParseRounds(handler.cfg, request.Form.Get("rounds"))
-	maxPlayers, maxPlayersInvalid := api.ParseMaxPlayers(handler.cfg, request.Form.Get("max_players"))
-	customWordsPerTurn, customWordsPerTurnInvalid := api.ParseCustomWordsPerTurn(handler.cfg, request.Form.Get("custom_words_per_turn"))
-	clientsPerIPLimit, clientsPerIPLimitInvalid := api.ParseClientsPerIPLimit(handler.cfg, request.Form.Get("clients_per_ip_limit"))
-	publicLobby, publicLobbyInvalid := api.ParseBoolean("public", request.Form.Get("public"))
-	wordsPerTurn, wordsPerTurnInvalid := api.ParseWordsPerTurn(handler.cfg, request.Form.Get("words_per_turn"))
-
-	if wordsPerTurn < customWordsPerTurn {
-		wordsPerTurnInvalid = errors.New("words per turn must be greater than or equal to custom words per turn")
-	}
-
-	var lowercaser cases.Caser
-	if languageInvalid != nil {
-		lowercaser = cases.Lower(language.English)
-	} else {
-		lowercaser = languageData.Lowercaser()
-	}
-	customWords, customWordsInvalid := api.ParseCustomWords(lowercaser, request.Form.Get("custom_words"))
+	settings, settingsErrors := api.ParseLobbySettings(handler.cfg, request.Form, config.LobbySettingDefaults{})
 
 	// Prevent resetting the form, since that would be annoying as hell.
 	pageData := IndexPageData{
@@ -208,45 +192,29 @@ func (handler *SSRHandler) ssrCreateLobby(writer http.ResponseWriter, request *h
 			ClientsPerIPLimit:  request.Form.Get("clients_per_ip_limit"),
 			Language:           request.Form.Get("language"),
 			ScoreCalculation:   request.Form.Get("score_calculation"),
+			GameMode:           request.Form.Get("game_mode"),
 			WordsPerTurn:       request.Form.Get("words_per_turn"),
+			AspectRatio:        request.Form.Get("aspect_ratio"),
+			MinBrushSize:       request.Form.Get("min_brush_size"),
+			MaxBrushSize:       request.Form.Get("max_brush_size"),
+			DrawersPerTurn:     request.Form.Get("drawers_per_turn"),
+			DrawerScore:        request.Form.Get("drawer_score"),
+			GuessTimeCap:       request.Form.Get("guess_time_cap"),
+			GuessedPercentage:  request.Form.Get("guessed_percentage"),
+			WordRerolls:        request.Form.Get("word_rerolls"),
+			DrawerIdleTimeout:  request.Form.Get("drawer_idle_timeout"),
+			IdleTurns:          request.Form.Get("idle_turns"),
+			DrawOrder:          request.Form.Get("draw_order"),
+			MinReadyPlayers:    request.Form.Get("min_ready_players"),
+			StartCountdown:     request.Form.Get("start_countdown"),
 		},
 		Languages:         game.SupportedLanguages,
 		ScoreCalculations: game.SupportedScoreCalculations,
-	}
-
-	if scoreCalculationInvalid != nil {
-		pageData.Errors = append(pageData.Errors, scoreCalculationInvalid.Error())
-	}
-	if languageInvalid != nil {
-		pageData.Errors = append(pageData.Errors, languageInvalid.Error())
-	}
-	if drawingTimeInvalid != nil {
-		pageData.Errors = append(pageData.Errors, drawingTimeInvalid.Error())
-	}
-	if roundsInvalid != nil {
-		pageData.Errors = append(pageData.Errors, roundsInvalid.Error())
-	}
-	if maxPlayersInvalid != nil {
-		pageData.Errors = append(pageData.Errors, maxPlayersInvalid.Error())
-	}
-	if customWordsInvalid != nil {
-		pageData.Errors = append(pageData.Errors, customWordsInvalid.Error())
-	}
-	if customWordsPerTurnInvalid != nil {
-		pageData.Errors = append(pageData.Errors, customWordsPerTurnInvalid.Error())
-	} else {
-		if languageRawValue == "custom" && len(customWords) == 0 {
-			pageData.Errors = append(pageData.Errors, "custom words must be provided when using custom language")
-		}
-	}
-	if clientsPerIPLimitInvalid != nil {
-		pageData.Errors = append(pageData.Errors, clientsPerIPLimitInvalid.Error())
-	}
-	if publicLobbyInvalid != nil {
-		pageData.Errors = append(pageData.Errors, publicLobbyInvalid.Error())
-	}
-	if wordsPerTurnInvalid != nil {
-		pageData.Errors = append(pageData.Errors, wordsPerTurnInvalid.Error())
+		GameModes:         game.SupportedGameModes,
+		DrawOrders:        game.SupportedDrawOrders,
+		AspectRatios:      game.SupportedAspectRatios,
+		MaxPasswordLength: game.MaxPasswordLength,
+		Errors:            settingsErrors,
 	}
 
 	translation, locale := determineTranslation(request)
@@ -263,17 +231,8 @@ func (handler *SSRHandler) ssrCreateLobby(writer http.ResponseWriter, request *h
 
 	playerName := api.GetPlayername(request)
 
-	lobbySettings := &game.EditableLobbySettings{
-		Rounds:             rounds,
-		DrawingTime:        drawingTime,
-		MaxPlayers:         maxPlayers,
-		CustomWordsPerTurn: customWordsPerTurn,
-		ClientsPerIPLimit:  clientsPerIPLimit,
-		Public:             publicLobby,
-		WordsPerTurn:       wordsPerTurn,
-	}
-	player, lobby, err := game.CreateLobby("", playerName, languageKey,
-		lobbySettings, customWords, scoreCalculation)
+	player, lobby, err := game.CreateLobby("", playerName, settings.LanguageKey,
+		&settings.Editable, settings.CustomWords, settings.ScoreCalculation, settings.GameMode)
 	if err != nil {
 		pageData.Errors = append(pageData.Errors, err.Error())
 		if err := pageTemplates.ExecuteTemplate(writer, "index", pageData); err != nil {
@@ -283,8 +242,10 @@ func (handler *SSRHandler) ssrCreateLobby(writer http.ResponseWriter, request *h
 		return
 	}
 
-	lobby.WriteObject = api.WriteObject
-	lobby.WritePreparedMessage = api.WritePreparedMessage
+	if err := api.ApplyLobbySettings(handler.cfg, lobby, settings); err != nil {
+		handler.userFacingError(writer, err.Error(), translation)
+		return
+	}
 	player.SetLastKnownAddress(api.GetIPAddressFromRequest(request))
 	api.SetGameplayCookies(writer, request, player, lobby)
 
@@ -27,6 +27,15 @@ document.getElementById("lobby-create").addEventListener("submit", (event) => {
         check_box.removeAttribute("checked");
     }
 
+    // The server doesn't know the timezone of the user, so we have to pass
+    // the scheduled start as an absolute timestamp.
+    const scheduled_start_local = document.getElementById(
+        "scheduled_start_local",
+    ).value;
+    document.getElementById("scheduled_start").value = scheduled_start_local
+        ? new Date(scheduled_start_local).toISOString()
+        : "";
+
     return true;
 });
 
@@ -122,6 +131,16 @@ const set_lobbies = (lobbies, visible) => {
                 new_custom_tag('{{.Translation.Get "custom-words"}}'),
             );
         }
+        if (lobby.permanent) {
+            lobby_list_row_a.appendChild(
+                new_custom_tag('{{.Translation.Get "permanent-room"}}'),
+            );
+        }
+        if (lobby.hasPassword) {
+            lobby_list_row_a.appendChild(
+                new_custom_tag('{{.Translation.Get "lobby-password"}}'),
+            );
+        }
         if (lobby.state === "ongoing") {
             lobby_list_row_a.appendChild(
                 new_custom_tag('{{.Translation.Get "ongoing"}}'),
@@ -231,6 +250,31 @@ document
     .getElementById("refresh-lobby-list-button")
     .addEventListener("click", refresh_lobby_list);
 
+document.getElementById("quick-play-button").addEventListener("click", () => {
+    fetch(`${rootPath}/v1/matchmake`, {
+        method: "POST",
+        body: new URLSearchParams({
+            language: document.getElementById("language").value,
+        }),
+    }).then((response) => {
+        if (response.status !== 200) {
+            response.text().then((bodyText) => alert(bodyText));
+            return;
+        }
+        response.json().then((lobbyData) => {
+            window.location.href = `${rootPath}/lobby/${lobbyData.lobbyCode}`;
+        });
+    });
+});
+
+// Lobby codes are accepted anywhere a lobby ID is, so we can simply navigate
+// to the lobby.
+document.getElementById("join-by-code").addEventListener("submit", (event) => {
+    event.preventDefault();
+    const code = document.getElementById("lobby-code-input").value.trim();
+    window.location.href = `${rootPath}/lobby/${encodeURIComponent(code)}`;
+});
+
 // Makes sure, that navigating back after creating a lobby also shows it in the list.
 window.addEventListener("pageshow", (event) => {
     if (event.persisted) {
@@ -20,6 +20,19 @@ type lobbyPageData struct {
 	Locale      string
 }
 
+// passwordPageData is the data password.html requires to be displayed.
+type passwordPageData struct {
+	*BasePageConfig
+
+	LobbyID           string
+	MaxPasswordLength int
+	// WrongPassword is set if the user has already tried a password.
+	WrongPassword bool
+
+	Translation *translations.Translation
+	Locale      string
+}
+
 type lobbyJsData struct {
 	*BasePageConfig
 	*api.GameConstants
@@ -77,11 +90,40 @@ func (handler *SSRHandler) ssrEnterLobbyNoChecks(
 	translation, locale := determineTranslation(request)
 	requestAddress := api.GetIPAddressFromRequest(request)
 
+	if err := request.ParseForm(); err != nil {
+		handler.userFacingError(writer, err.Error(), translation)
+		return
+	}
+	password := request.Form.Get("password")
+	passwordValid, err := api.CheckJoinPassword(lobby, request, func() bool {
+		return getPlayer() != nil
+	})
+	if err != nil {
+		writer.WriteHeader(http.StatusTooManyRequests)
+		handler.userFacingError(writer, translation.Get("too-many-password-attempts"), translation)
+		return
+	}
+
 	var pageData *lobbyPageData
 	lobby.Synchronized(func() {
 		player := getPlayer()
 
 		if player == nil {
+			if !passwordValid && !lobby.IsInviteTokenValid(request.Form.Get("invite_token")) {
+				err := pageTemplates.ExecuteTemplate(writer, "password-page", &passwordPageData{
+					BasePageConfig:    handler.basePageConfig,
+					LobbyID:           lobby.LobbyID,
+					MaxPasswordLength: game.MaxPasswordLength,
+					WrongPassword:     password != "",
+					Translation:       translation,
+					Locale:            locale,
+				})
+				if err != nil {
+					log.Printf("Error templating password page: %s\n", err)
+				}
+				return
+			}
+
 			if !lobby.HasFreePlayerSlot() {
 				handler.userFacingError(writer, translation.Get("lobby-full"), translation)
 				return
@@ -117,6 +159,13 @@ func (handler *SSRHandler) ssrEnterLobbyNoChecks(
 	// In this case we don't want to template the lobby, since an error has occurred
 	// and probably already has been handled.
 	if pageData != nil {
+		// Passwords are submitted via POST, but reloading the page shouldn't
+		// resubmit the form.
+		if request.Method == http.MethodPost {
+			http.Redirect(writer, request, handler.basePageConfig.RootPath+"/lobby/"+lobby.LobbyID, http.StatusSeeOther)
+			return
+		}
+
 		if err := pageTemplates.ExecuteTemplate(writer, "lobby-page", pageData); err != nil {
 			log.Printf("Error templating lobby: %s\n", err)
 		}
@@ -67,9 +67,11 @@ const gameOverDialog = document.getElementById("game-over-dialog");
 const gameOverDialogTitle = document.getElementById("game-over-dialog-title");
 const gameOverScoreboard = document.getElementById("game-over-scoreboard");
 const forceRestartButton = document.getElementById("force-restart-button");
+const cloneLobbyButton = document.getElementById("clone-lobby-button");
 const wordDialog = document.getElementById("word-dialog");
 const wordPreSelected = document.getElementById("word-preselected");
 const wordButtonContainer = document.getElementById("word-button-container");
+const wordRerollButton = document.getElementById("word-reroll-button");
 
 const kickDialog = document.getElementById("kick-dialog");
 const kickDialogPlayers = document.getElementById("kick-dialog-players");
@@ -239,6 +241,14 @@ document
     .getElementById("kick-button")
     .addEventListener("click", showKickDialog);
 
+document.getElementById("skip-button").addEventListener("click", () => {
+    socket.send(
+        JSON.stringify({
+            type: "skip-vote",
+        }),
+    );
+});
+
 function hideKickDialog() {
     kickDialog.style.visibility = "hidden";
 }
@@ -360,6 +370,31 @@ document
     .getElementById("lobby-settings-save-button")
     .addEventListener("click", saveLobbySettings);
 
+function createInviteLink() {
+    fetch(`${rootPath}/v1/lobby/invite`, {
+        method: "POST",
+    }).then((result) => {
+        if (result.status === 200) {
+            result.json().then((invite) => {
+                const inviteURL = new URL(window.location.href);
+                inviteURL.search = new URLSearchParams({
+                    invite_token: invite.token,
+                });
+                const inviteLink = document.getElementById("invite-link");
+                inviteLink.value = inviteURL.toString();
+                inviteLink.select();
+            });
+        } else {
+            result.text().then((bodyText) => {
+                alert("Error creating invite link: \n\n - " + bodyText);
+            });
+        }
+    });
+}
+document
+    .getElementById("create-invite-link-button")
+    .addEventListener("click", createInviteLink);
+
 function toggleSound() {
     sound = !sound;
     localStorage.setItem("sound", sound.toString());
@@ -396,8 +431,11 @@ function updateTogglePenIcon() {
 
 //The drawing board has a base size. This base size results in a certain ratio
 //that the actual canvas has to be resized accordingly too. This is needed
-//since not every client has the same screensize.
-const baseWidth = 1600;
+//since not every client has the same screensize. Since the base size depends
+//on the lobby, it is part of the lobby page.
+const baseWidth = drawingBoard.width;
+document.getElementById("drawing-board-wrapper").style.paddingTop =
+    (drawingBoard.height / drawingBoard.width) * 100 + "%";
 
 // Moving this here to extract the context after resizing
 const context = drawingBoard.getContext("2d", { alpha: false });
@@ -438,14 +476,13 @@ let spectateRequested = false;
 //Initially, we require some values to avoid running into nullpointers
 //or undefined errors. The specific values don't really matter.
 let localTool = pen;
-let localLineWidth = 8;
-
 //Those are not scaled for now, as the whole toolbar would then have to incorrectly size up and down.
-const sizeButton8 = document.getElementById("size-8-button");
-const sizeButton16 = document.getElementById("size-16-button");
-const sizeButton24 = document.getElementById("size-24-button");
-const sizeButton32 = document.getElementById("size-32-button");
+//The sizes depend on the lobby, so they are part of the lobby page.
+const sizeButtonList = [0, 1, 2, 3].map((index) =>
+    document.getElementById(`size-${index}-button`),
+);
 const sizeButtons = document.getElementById("size-buttons");
+let localLineWidth = Number(sizeButtonList[0].dataset.size);
 
 const toolButtonPen = document.getElementById("tool-type-pencil");
 const toolButtonRubber = document.getElementById("tool-type-rubber");
@@ -455,10 +492,6 @@ const pencilImage = document.getElementById("use-pencil-button-image");
 const eraserImage = document.getElementById("use-eraser-button-image");
 const bucketImage = document.getElementById("use-fill-bucket-button-image");
 const undoImage = document.getElementById("undo-button-image");
-const size8buttonWrapper = document.getElementById("size-8-button-wrapper");
-const size16buttonWrapper = document.getElementById("size-16-button-wrapper");
-const size24buttonWrapper = document.getElementById("size-24-button-wrapper");
-const size32buttonWrapper = document.getElementById("size-32-button-wrapper");
 
 pencilImage.setAttribute(
     "title",
@@ -477,15 +510,11 @@ undoImage.setAttribute(
     `${undoImage.getAttribute("title")} (${keyboardManager.get("undoModifier")}+${keyboardManager.get("undo")})`,
 );
 
-if (sizeButton8.checked) {
-    setLineWidthNoUpdate(8);
-} else if (sizeButton16.checked) {
-    setLineWidthNoUpdate(16);
-} else if (sizeButton24.checked) {
-    setLineWidthNoUpdate(24);
-} else if (sizeButton32.checked) {
-    setLineWidthNoUpdate(32);
-}
+sizeButtonList.forEach((sizeButton) => {
+    if (sizeButton.checked) {
+        setLineWidthNoUpdate(Number(sizeButton.dataset.size));
+    }
+});
 
 if (toolButtonPen.checked) {
     chooseToolNoUpdate(pen);
@@ -526,6 +555,34 @@ for (let i = 0; i < secondColorButtonRow.children.length; i++) {
     secondColorButtonRow.children[i].addEventListener("click", _setColor);
 }
 
+// applyPalette replaces the default colors with the custom palette of the
+// lobby. For palettes larger than the default one, only the first colors
+// can be chosen, but all colors are drawn correctly.
+function applyPalette(palette) {
+    setPalette(palette);
+    rubberColor = indexToRgbColor(0);
+
+    const colorButtons = [
+        ...firstColorButtonRow.children,
+        ...secondColorButtonRow.children,
+    ];
+    colorButtons.forEach((button, index) => {
+        if (index < palette.length) {
+            button.style.setProperty("background-color", palette[index], "important");
+            button.style.display = "";
+        } else {
+            button.style.display = "none";
+        }
+    });
+
+    if (localColorIndex >= palette.length) {
+        setColorNoUpdate(palette.length - 1);
+    } else {
+        setColorNoUpdate(localColorIndex);
+    }
+    updateDrawingStateUI();
+}
+
 function setColorNoUpdate(index) {
     localColorIndex = index;
     localColor = indexToRgbColor(index);
@@ -542,18 +599,22 @@ function setLineWidth(value) {
     updateDrawingStateUI();
 }
 
-sizeButton8.addEventListener("change", () => setLineWidth(8));
-size8buttonWrapper.addEventListener("mouseup", sizeButton8.click);
-size8buttonWrapper.addEventListener("mousedown", sizeButton8.click);
-sizeButton16.addEventListener("change", () => setLineWidth(16));
-size16buttonWrapper.addEventListener("mouseup", sizeButton16.click);
-size16buttonWrapper.addEventListener("mousedown", sizeButton16.click);
-sizeButton24.addEventListener("change", () => setLineWidth(24));
-size24buttonWrapper.addEventListener("mouseup", sizeButton24.click);
-size24buttonWrapper.addEventListener("mousedown", sizeButton24.click);
-sizeButton32.addEventListener("change", () => setLineWidth(32));
-size32buttonWrapper.addEventListener("mouseup", sizeButton32.click);
-size32buttonWrapper.addEventListener("mousedown", sizeButton32.click);
+sizeButtonList.forEach((sizeButton, index) => {
+    const size = Number(sizeButton.dataset.size);
+    const sizeButtonWrapper = document.getElementById(
+        `size-${index}-button-wrapper`,
+    );
+    sizeButton.addEventListener("change", () => setLineWidth(size));
+    sizeButtonWrapper.addEventListener("mouseup", sizeButton.click);
+    sizeButtonWrapper.addEventListener("mousedown", sizeButton.click);
+});
+
+// selectSizeButton is used for keyboard shortcuts, which are still named
+// after the default sizes.
+function selectSizeButton(index) {
+    sizeButtonList[index].click();
+    setLineWidth(Number(sizeButtonList[index].dataset.size));
+}
 
 function setLineWidthNoUpdate(value) {
     localLineWidth = value;
@@ -607,7 +668,8 @@ function numberTo16BitHexadecimal(number) {
     return Number(number).toString(16).padStart(2, "0");
 }
 
-const rubberColor = { r: 255, g: 255, b: 255 };
+// The rubber always uses the canvas color, which is the first color.
+let rubberColor = indexToRgbColor(0);
 
 function updateDrawingStateUI() {
     // Color all buttons, so the player always has a hint as to what the
@@ -794,8 +856,97 @@ function forceStartGame() {
 forceStartButton.addEventListener("click", forceStartGame);
 forceRestartButton.addEventListener("click", forceStartGame);
 
+function cloneLobby() {
+    fetch(`${rootPath}/v1/lobby/clone`, {
+        method: "POST",
+    }).then((result) => {
+        if (result.status === 200) {
+            result.json().then((lobby) => {
+                window.location.href = `${rootPath}/lobby/${lobby.lobbyCode}`;
+            });
+        } else {
+            result.text().then((bodyText) => {
+                alert("Error cloning lobby: \n\n - " + bodyText);
+            });
+        }
+    });
+}
+cloneLobbyButton.addEventListener("click", cloneLobby);
+
+const lobbyClonedDialogId = "lobby-cloned-dialog";
+function showLobbyClonedDialog(clonedLobby) {
+    const joinURL = new URL(
+        `${rootPath}/lobby/${clonedLobby.lobbyCode}`,
+        window.location.href,
+    );
+    if (clonedLobby.inviteToken) {
+        joinURL.search = new URLSearchParams({
+            invite_token: clonedLobby.inviteToken,
+        });
+    }
+
+    const joinButton = createDialogButton(
+        '{{.Translation.Get "join-new-lobby"}}',
+    );
+    joinButton.addEventListener("click", () => {
+        window.location.href = joinURL.toString();
+    });
+    const closeButton = createDialogButton('{{.Translation.Get "close"}}');
+    closeButton.addEventListener("click", () => {
+        closeDialog(lobbyClonedDialogId);
+    });
+
+    const messageNode = document.createElement("span");
+    messageNode.innerText = '{{.Translation.Get "lobby-cloned-text"}}';
+
+    closeDialog(lobbyClonedDialogId);
+    showDialog(
+        lobbyClonedDialogId,
+        '{{.Translation.Get "lobby-cloned-title"}}',
+        messageNode,
+        createDialogButtonBar(joinButton, closeButton),
+    );
+}
+
+// createSeriesHistoryNode lists the winners of all finished games of the
+// series, newest first.
+function createSeriesHistoryNode(series) {
+    const historyNode = document.createElement("div");
+    historyNode.classList.add("series-history");
+
+    const titleNode = document.createElement("b");
+    titleNode.innerText = '{{.Translation.Get "series-history"}}';
+    historyNode.appendChild(titleNode);
+
+    series
+        .slice()
+        .reverse()
+        .forEach((game) => {
+            const winners = (game.results || [])
+                .filter((result) => result.rank === 1)
+                .map((result) => `${result.playerName} (${result.score})`);
+
+            const gameNode = document.createElement("div");
+            gameNode.innerText =
+                '{{.Translation.Get "series-game"}}'.format(game.number) +
+                ": " +
+                winners.join(", ");
+            historyNode.appendChild(gameNode);
+        });
+
+    return historyNode;
+}
+
+function canClear() {
+    // The board is shared on whiteboards, so only the owner may clear it.
+    if (gameMode === "whiteboard") {
+        return allowDrawing && ownerID === ownID;
+    }
+    return allowDrawing;
+}
+
 function clearCanvasAndSendEvent() {
-    if (allowDrawing) {
+    if (canClear()) {
         //Avoid unnecessary traffic back to us and handle the clear directly.
         clear(context);
         socket.send(
@@ -805,9 +956,8 @@ function clearCanvasAndSendEvent() {
         );
     }
 }
-document
-    .getElementById("clear-canvas-button")
-    .addEventListener("click", clearCanvasAndSendEvent);
+const clearCanvasButton = document.getElementById("clear-canvas-button");
+clearCanvasButton.addEventListener("click", clearCanvasAndSendEvent);
 
 function undoAndSendEvent() {
     if (allowDrawing) {
@@ -877,6 +1027,7 @@ messageInput.addEventListener("keydown", function (event) {
 function setAllowDrawing(value) {
     allowDrawing = value;
     updateDrawingStateUI();
+    updateButtonVisibilities();
 
     if (allowDrawing) {
         document.getElementById("toolbox").style.display = "flex";
@@ -896,6 +1047,15 @@ function chooseWord(index) {
     wordDialog.style.visibility = "hidden";
 }
 
+function revealHint(index) {
+    socket.send(
+        JSON.stringify({
+            type: "reveal-hint",
+            data: index,
+        }),
+    );
+}
+
 function onVotekickPlayer(playerId) {
     socket.send(
         JSON.stringify({
@@ -916,6 +1076,13 @@ new MutationObserver(
 });
 
 let ownID, ownerID, ownName, drawerID, drawerName;
+// If greater than 0, the game starts once this many players are ready,
+// instead of requiring all players to be ready.
+let minReadyPlayers = 0;
+// On whiteboards, there are no turns and everyone can draw at once.
+let gameMode = "classic";
+// Chains of the last telephone game, set by the reveal event.
+let telephoneChains = null;
 let round = 0;
 let rounds = 0;
 let roundEndTime = 0;
@@ -938,12 +1105,49 @@ const handleEvent = (parsed) => {
                 null,
                 `{{.Translation.Get "guessers-disconnected"}}`,
             );
+        } else if (parsed.data.roundEndReason === "turn_skipped") {
+            appendMessage(
+                "system-message",
+                null,
+                `{{.Translation.Get "turn-skipped"}}`,
+            );
+        } else if (parsed.data.roundEndReason === "drawer_idle") {
+            appendMessage(
+                "system-message",
+                null,
+                `{{.Translation.Get "drawer-idle"}}`,
+            );
         } else {
             showRoundEndMessage(ready.previousWord);
         }
         handleReadyEvent(ready);
     } else if (parsed.type === "update-players") {
         applyPlayers(parsed.data);
+    } else if (parsed.type === "start-countdown") {
+        showStartCountdown(parsed.data.timeLeft);
+    } else if (parsed.type === "start-countdown-cancelled") {
+        hideStartCountdown();
+    } else if (parsed.type === "telephone-step") {
+        hideStartCountdown();
+        hideScheduledStart();
+        clear(context);
+        closeDialog(telephoneDialogId);
+        setRoundTimeLeft(parsed.data.timeLeft);
+        handleTelephoneStep(parsed.data);
+    } else if (parsed.type === "telephone-submitted") {
+        if (parsed.data.playerId === ownID) {
+            finishTelephoneStep();
+        }
+        appendMessage(
+            "system-message",
+            '{{.Translation.Get "system"}}',
+            '{{.Translation.Get "telephone-submitted"}}'.format(
+                parsed.data.submitted,
+                parsed.data.total,
+            ),
+        );
+    } else if (parsed.type === "telephone-reveal") {
+        telephoneChains = parsed.data;
     } else if (parsed.type === "name-change") {
         const player = getCachedPlayer(parsed.data.playerId);
         if (player !== null) {
@@ -1059,6 +1263,14 @@ const handleEvent = (parsed) => {
         ) {
             context.putImageData(imageData, 0, 0);
         }
+    } else if (
+        parsed.type === "rectangle" ||
+        parsed.type === "ellipse" ||
+        parsed.type === "straight-line"
+    ) {
+        applyDrawElements([parsed]);
+    } else if (parsed.type === "drawing-batch") {
+        applyDrawElements(parsed.data);
     } else if (parsed.type === "clear-drawing-board") {
         clear(context);
     } else if (parsed.type === "word-chosen") {
@@ -1066,8 +1278,12 @@ const handleEvent = (parsed) => {
         waitChooseDialog.style.visibility = "hidden";
         setRoundTimeLeft(parsed.data.timeLeft);
         applyWordHints(parsed.data.hints);
-        setAllowDrawing(drawerID === ownID);
+        setAllowDrawing(isOwnPlayerDrawing());
+    } else if (parsed.type === "update-time-left") {
+        setRoundTimeLeft(parsed.data);
     } else if (parsed.type === "next-turn") {
+        hideStartCountdown();
+        hideScheduledStart();
         if (gameState === "ongoing") {
             if (parsed.data.roundEndReason === "drawer_disconnected") {
                 appendMessage(
@@ -1081,6 +1297,18 @@ const handleEvent = (parsed) => {
                     null,
                     `{{.Translation.Get "guessers-disconnected"}}`,
                 );
+            } else if (parsed.data.roundEndReason === "turn_skipped") {
+                appendMessage(
+                    "system-message",
+                    null,
+                    `{{.Translation.Get "turn-skipped"}}`,
+                );
+            } else if (parsed.data.roundEndReason === "drawer_idle") {
+                appendMessage(
+                    "system-message",
+                    null,
+                    `{{.Translation.Get "drawer-idle"}}`,
+                );
             } else {
                 showRoundEndMessage(parsed.data.previousWord);
             }
@@ -1094,6 +1322,7 @@ const handleEvent = (parsed) => {
         //ongoing, are not visible anymore.
         startDialog.style.visibility = "hidden";
         forceRestartButton.style.display = "none";
+        cloneLobbyButton.style.display = "none";
         gameOverDialog.style.visibility = "hidden";
 
         //If a player doesn't choose, the dialog will still be up.
@@ -1148,6 +1377,21 @@ const handleEvent = (parsed) => {
                 kickMessage,
             );
         }
+    } else if (parsed.type === "idle-spectating") {
+        appendMessage(
+            "system-message",
+            '{{.Translation.Get "system"}}',
+            '{{.Translation.Get "idle-spectating"}}',
+        );
+    } else if (parsed.type === "skip-vote") {
+        appendMessage(
+            "system-message",
+            '{{.Translation.Get "system"}}',
+            '{{.Translation.Get "skip-vote"}}'.format(
+                parsed.data.voteCount,
+                parsed.data.requiredVoteCount,
+            ),
+        );
     } else if (parsed.type === "owner-change") {
         ownerID = parsed.data.playerId;
         updateButtonVisibilities();
@@ -1158,6 +1402,11 @@ const handleEvent = (parsed) => {
                 parsed.data.playerName,
             ),
         );
+    } else if (parsed.type === "lobby-cloned") {
+        // The owner is redirected to the new lobby anyway.
+        if (ownerID !== ownID) {
+            showLobbyClonedDialog(parsed.data);
+        }
     } else if (parsed.type === "drawer-kicked") {
         appendMessage(
             "system-message",
@@ -1222,6 +1471,13 @@ function showRoundEndMessage(previousWord) {
     }
 }
 
+// isOwnPlayerDrawing is true for the drawer and for players assisting the
+// drawer in cooperative turns.
+function isOwnPlayerDrawing() {
+    const self = getCachedPlayer(ownID);
+    return self !== null && self.state === "drawing";
+}
+
 function getCachedPlayer(playerID) {
     if (!cachedPlayers) {
         return null;
@@ -1253,9 +1509,96 @@ function setRoundTimeLeft(timeLeftMs) {
     roundEndTime = Date.now() + timeLeftMs;
 }
 
+// startCountdownInterval updates the countdown displays while the countdown
+// before the first turn is running.
+let startCountdownInterval;
+
+function showStartCountdown(timeLeftMs) {
+    clearInterval(startCountdownInterval);
+
+    const endTime = Date.now() + timeLeftMs;
+    const updateSecondsLeft = () => {
+        const secondsLeft = Math.max(
+            0,
+            Math.ceil((endTime - Date.now()) / 1000),
+        );
+        Array.from(
+            document.getElementsByClassName("start-countdown-seconds"),
+        ).forEach((element) => {
+            element.innerText = secondsLeft.toString();
+        });
+    };
+    updateSecondsLeft();
+    startCountdownInterval = setInterval(updateSecondsLeft, 250);
+
+    Array.from(document.getElementsByClassName("start-countdown")).forEach(
+        (element) => {
+            element.style.display = "block";
+        },
+    );
+}
+
+function hideStartCountdown() {
+    clearInterval(startCountdownInterval);
+    Array.from(document.getElementsByClassName("start-countdown")).forEach(
+        (element) => {
+            element.style.display = "none";
+        },
+    );
+}
+
+// scheduledStartInterval updates the time left until the scheduled start of
+// the game, while the start dialog is shown.
+let scheduledStartInterval;
+
+function showScheduledStart(timeLeftMs) {
+    clearInterval(scheduledStartInterval);
+
+    const scheduledStart = document.querySelector(".scheduled-start");
+    const timeLeftNode = document.getElementById("scheduled-start-time-left");
+    const startTime = Date.now() + timeLeftMs;
+    const updateTimeLeft = () => {
+        const secondsLeft = Math.ceil((startTime - Date.now()) / 1000);
+        if (secondsLeft <= 0) {
+            hideScheduledStart();
+            return;
+        }
+
+        const hours = Math.floor(secondsLeft / 3600);
+        const minutes = Math.floor((secondsLeft % 3600) / 60);
+        const seconds = secondsLeft % 60;
+        timeLeftNode.innerText =
+            hours.toString() +
+            ":" +
+            minutes.toString().padStart(2, "0") +
+            ":" +
+            seconds.toString().padStart(2, "0");
+    };
+    updateTimeLeft();
+    scheduledStartInterval = setInterval(updateTimeLeft, 1000);
+    scheduledStart.style.display = "block";
+}
+
+function hideScheduledStart() {
+    clearInterval(scheduledStartInterval);
+    document.querySelector(".scheduled-start").style.display = "none";
+}
+
 const handleReadyEvent = (ready) => {
     ownerID = ready.ownerId;
     ownID = ready.playerId;
+    minReadyPlayers = ready.minReadyPlayers;
+    if (ready.startCountdown > 0) {
+        showStartCountdown(ready.startCountdown);
+    } else {
+        hideStartCountdown();
+    }
+    if (ready.gameState === "unstarted" && ready.scheduledStart > 0) {
+        showScheduledStart(ready.scheduledStart);
+    } else {
+        hideScheduledStart();
+    }
+    gameMode = ready.gameMode;
 
     setRoundTimeLeft(ready.timeLeft);
     setUsernameLocally(ready.playerName);
@@ -1269,6 +1612,9 @@ const handleReadyEvent = (ready) => {
     if (ready.players && ready.players.length) {
         applyPlayers(ready.players);
     }
+    if (ready.palette && ready.palette.length) {
+        applyPalette(ready.palette);
+    }
     if (ready.currentDrawing && ready.currentDrawing.length) {
         applyDrawData(ready.currentDrawing);
     }
@@ -1289,10 +1635,29 @@ const handleReadyEvent = (ready) => {
         gameOverDialog.style.visibility = "visible";
         if (ownerID === ownID) {
             forceRestartButton.style.display = "block";
+            cloneLobbyButton.style.display = "block";
         }
 
         gameOverScoreboard.innerHTML = "";
 
+        // Telephone games don't have scores, so we show the chains instead.
+        if (gameMode === "telephone") {
+            finishTelephoneStep();
+            if (ready.telephoneChains) {
+                telephoneChains = ready.telephoneChains;
+            }
+            gameOverDialogTitle.innerText = `{{.Translation.Get "telephone-reveal"}}`;
+            if (telephoneChains) {
+                gameOverScoreboard.replaceChildren(
+                    ...telephoneChains.map(createTelephoneChainNode),
+                );
+            }
+            return;
+        }
+
+        // Series scores are only interesting after more than one game.
+        const showSeries = ready.series && ready.series.length > 1;
+
         //Copying array so we can sort.
         const players = cachedPlayers.slice();
         players.sort((a, b) => {
@@ -1340,10 +1705,28 @@ const handleReadyEvent = (ready) => {
                 scoreboardScoreSpan.innerText = player.score;
                 newScoreboardEntry.appendChild(scoreboardScoreSpan);
 
+                if (showSeries) {
+                    const seriesScoreSpan = document.createElement("span");
+                    seriesScoreSpan.classList.add(
+                        "gameover-scoreboard-series-score",
+                    );
+                    seriesScoreSpan.innerText =
+                        '{{.Translation.Get "series-score"}}'.format(
+                            player.seriesScore,
+                        );
+                    newScoreboardEntry.appendChild(seriesScoreSpan);
+                }
+
                 gameOverScoreboard.appendChild(newScoreboardEntry);
             }
         }
 
+        if (showSeries) {
+            gameOverScoreboard.appendChild(
+                createSeriesHistoryNode(ready.series),
+            );
+        }
+
         if (selfPlayer.rank === 1) {
             if (countOfRankOnePlayers >= 2) {
                 gameOverDialogTitle.innerText = `{{.Translation.Get "game-over-tie"}}`;
@@ -1359,19 +1742,135 @@ const handleReadyEvent = (ready) => {
         }
     } else if (ready.gameState === "ongoing") {
         // Lack of wordHints implies that word has been chosen yet.
-        if (!ready.wordHints && drawerID !== ownID) {
+        if (gameMode === "classic" && !ready.wordHints && drawerID !== ownID) {
             waitChooseDrawerSpan.innerText = drawerName;
             waitChooseDialog.style.visibility = "visible";
         }
+        if (ready.telephoneStep) {
+            handleTelephoneStep(ready.telephoneStep);
+        }
     }
 };
 
+const telephoneDialogId = "telephone-dialog";
+const telephoneSubmitButton = document.getElementById(
+    "telephone-submit-button",
+);
+telephoneSubmitButton.addEventListener("click", () => {
+    sendTelephoneSubmission("");
+});
+
+function sendTelephoneSubmission(text) {
+    socket.send(
+        JSON.stringify({
+            type: "telephone-submit",
+            data: { text: text },
+        }),
+    );
+}
+
+// handleTelephoneStep shows the task of the current telephone step. Text
+// tasks are shown in a dialog, while drawing tasks use the regular canvas.
+function handleTelephoneStep(step) {
+    if (step.submitted) {
+        finishTelephoneStep();
+        return;
+    }
+
+    if (step.task === "draw") {
+        wordContainer.innerText = '{{.Translation.Get "telephone-draw"}}'.format(
+            step.text,
+        );
+        telephoneSubmitButton.style.display = "";
+        setAllowDrawing(true);
+        return;
+    }
+
+    setAllowDrawing(false);
+
+    const content = document.createElement("div");
+    if (step.task === "describe") {
+        content.appendChild(createTelephoneDrawingNode(step.drawing || []));
+    }
+    const textInput = document.createElement("input");
+    textInput.type = "text";
+    textInput.maxLength = {{.MaxTelephoneTextLength}};
+    textInput.classList.add("input-item");
+    content.appendChild(textInput);
+
+    const submitButton = createDialogButton(
+        '{{.Translation.Get "telephone-submit"}}',
+    );
+    submitButton.addEventListener("click", () => {
+        sendTelephoneSubmission(textInput.value);
+    });
+
+    showDialog(
+        telephoneDialogId,
+        step.task === "prompt"
+            ? '{{.Translation.Get "telephone-prompt"}}'
+            : '{{.Translation.Get "telephone-describe"}}',
+        content,
+        createDialogButtonBar(submitButton),
+    );
+}
+
+function finishTelephoneStep() {
+    closeDialog(telephoneDialogId);
+    telephoneSubmitButton.style.display = "none";
+    wordContainer.innerText = "";
+    setAllowDrawing(false);
+}
+
+function createTelephoneDrawingNode(drawElements) {
+    const preview = document.createElement("canvas");
+    preview.width = drawingBoard.width;
+    preview.height = drawingBoard.height;
+    preview.classList.add("telephone-drawing");
+
+    const previewContext = preview.getContext("2d", { alpha: false });
+    previewContext.fillStyle = indexToHexColor(0);
+    previewContext.fillRect(0, 0, preview.width, preview.height);
+    drawElementsOnto(
+        previewContext,
+        previewContext.getImageData(0, 0, preview.width, preview.height),
+        drawElements,
+    );
+    return preview;
+}
+
+function createTelephoneChainNode(chain) {
+    const chainNode = document.createElement("div");
+    chainNode.classList.add("telephone-chain");
+    chain.entries.forEach((entry) => {
+        const authorNode = document.createElement("span");
+        authorNode.classList.add("telephone-author");
+        authorNode.innerText = entry.authorName;
+        chainNode.appendChild(authorNode);
+
+        if (entry.drawing) {
+            chainNode.appendChild(createTelephoneDrawingNode(entry.drawing));
+        } else {
+            const textNode = document.createElement("span");
+            textNode.innerText = entry.text || "…";
+            chainNode.appendChild(textNode);
+        }
+    });
+    return chainNode;
+}
+
 function updateButtonVisibilities() {
     if (ownerID === ownID) {
         lobbySettingsButton.style.display = "flex";
     } else {
         lobbySettingsButton.style.display = "none";
     }
+
+    if (canClear()) {
+        clearCanvasButton.style.display = "";
+    } else {
+        clearCanvasButton.style.display = "none";
+    }
 }
 
 function promptWords(data) {
@@ -1385,9 +1884,18 @@ function promptWords(data) {
             return button;
         }),
     );
+    wordRerollButton.style.display = data.rerollsLeft > 0 ? "" : "none";
     wordDialog.style.visibility = "visible";
 }
 
+wordRerollButton.addEventListener("click", () => {
+    socket.send(
+        JSON.stringify({
+            type: "reroll-words",
+        }),
+    );
+});
+
 function playWav(file) {
     if (sound) {
         const audio = new Audio(file);
@@ -1397,7 +1905,7 @@ function playWav(file) {
 }
 
 window.setInterval(() => {
-    if (gameState === "ongoing") {
+    if (gameState === "ongoing" && gameMode !== "whiteboard") {
         const msLeft = roundEndTime - Date.now();
         const secondsLeft = Math.max(0, Math.floor(msLeft / 1000));
         timeLeftValue.innerText = "" + secondsLeft;
@@ -1478,6 +1986,10 @@ function applyPlayers(players) {
             }
         });
 
+        if (minReadyPlayers > 0) {
+            readyPlayersRequired = minReadyPlayers;
+        }
+
         const readyCounts = document.getElementsByClassName("ready-count");
         const reaadyNeededs = document.getElementsByClassName("ready-needed");
 
@@ -1494,11 +2006,18 @@ function applyPlayers(players) {
         // Makes sure that the "is choosing" a word dialog doesn't show
         // "undefined" as the player name. Can happen, if the player
         // disconnects after being assigned the drawer.
-        if (matchOngoing && player.state === "drawing") {
+        // Players assisting the drawer in cooperative turns are drawing as
+        // well, but the drawer is the one choosing the word.
+        if (matchOngoing && player.state === "drawing" && !player.coDrawing) {
             drawerID = player.id;
             drawerName = player.name;
         }
 
+        // Toggling spectate mode applies instantly on whiteboards.
+        if (gameMode === "whiteboard" && player.id === ownID) {
+            setAllowDrawing(player.state === "drawing");
+        }
+
         //We don't wanna show the disconnected players.
         if (!player.connected) {
             return;
@@ -1617,7 +2136,7 @@ function updateRoundsDisplay() {
 }
 
 const applyWordHints = (wordHints, dummy) => {
-    const isDrawer = drawerID === ownID;
+    const isDrawer = isOwnPlayerDrawing();
 
     let wordLengths = [];
     let count = 0;
@@ -1652,6 +2171,11 @@ const applyWordHints = (wordHints, dummy) => {
 
             if (hint.revealed && isDrawer) {
                 hintSpan.classList.add("hint-revealed");
+            } else if (!dummy && isDrawer && hint.underline) {
+                // Letters hidden from guessers can be revealed manually.
+                hintSpan.classList.add("hint-revealable");
+                hintSpan.title = '{{.Translation.Get "reveal-hint"}}';
+                hintSpan.addEventListener("click", () => revealHint(index));
             }
 
             return hintSpan;
@@ -1684,7 +2208,17 @@ set_dummy_word_hints();
 
 const applyDrawData = (drawElements) => {
     clear(context);
+    applyDrawElements(drawElements);
+};
+
+// applyDrawElements draws on top of the current canvas, without clearing it.
+const applyDrawElements = (drawElements) => {
+    drawElementsOnto(context, imageData, drawElements);
+};
 
+// drawElementsOnto draws on top of any canvas, given its context and a
+// current copy of its image data.
+function drawElementsOnto(context, imageData, drawElements) {
     drawElements.forEach((drawElement) => {
         const drawData = drawElement.data;
         if (drawElement.type === "fill") {
@@ -1707,13 +2241,30 @@ const applyDrawData = (drawElements) => {
                 indexToRgbColor(drawData.color),
                 drawData.width,
             );
+        } else if (
+            drawElement.type === "rectangle" ||
+            drawElement.type === "ellipse" ||
+            drawElement.type === "straight-line"
+        ) {
+            drawShapeNoPut(
+                context,
+                imageData,
+                drawElement.type,
+                drawData.x,
+                drawData.y,
+                drawData.x2,
+                drawData.y2,
+                indexToRgbColor(drawData.color),
+                drawData.width,
+                drawData.filled,
+            );
         } else {
             console.log("Unknown draw element type: " + drawData.type);
         }
     });
 
     context.putImageData(imageData, 0, 0);
-};
+}
 
 let lastX = 0;
 let lastY = 0;
@@ -1798,7 +2349,10 @@ function pressureToLineWidth(event) {
     if (!penPressure || event.pressure === 0.5 || !event.pressure) {
         return localLineWidth;
     }
-    return Math.ceil(event.pressure * 32);
+    const maxLineWidth = Number(
+        sizeButtonList[sizeButtonList.length - 1].dataset.size,
+    );
+    return Math.ceil(event.pressure * maxLineWidth);
 }
 
 // Previously the onMouseMove handled leave, but we do this separately now for
@@ -1941,17 +2495,13 @@ function onKeyDown(event) {
         toolButtonRubber.click();
         chooseTool(rubber);
     } else if (event.key === keyboardManager.get("size8")) {
-        sizeButton8.click();
-        setLineWidth(8);
+        selectSizeButton(0);
     } else if (event.key === keyboardManager.get("size16")) {
-        sizeButton16.click();
-        setLineWidth(16);
+        selectSizeButton(1);
     } else if (event.key === keyboardManager.get("size24")) {
-        sizeButton24.click();
-        setLineWidth(24);
+        selectSizeButton(2);
     } else if (event.key === keyboardManager.get("size32")) {
-        sizeButton32.click();
-        setLineWidth(32);
+        selectSizeButton(3);
     } else if (
         getModifierKey(event, keyboardManager.get("undoModifier")) &&
         event.key.toLowerCase() === keyboardManager.get("undo")
@@ -1965,7 +2515,7 @@ function onKeyDown(event) {
 window.addEventListener("keydown", onKeyDown);
 
 function clear(context) {
-    context.fillStyle = "#FFFFFF";
+    context.fillStyle = indexToHexColor(0);
     context.fillRect(0, 0, drawingBoard.width, drawingBoard.height);
     // Refetch, as we don't manually fill here.
     imageData = context.getImageData(
@@ -152,6 +152,76 @@ function prepareDrawLineCoords(context, x1, y1, x2, y2, width) {
         bottom: bottom,
     };
 }
+// Shapes are drawn span by span, which is a lot cheaper than drawing
+// their outline via many thick lines.
+function drawShapeNoPut(context, imageData, type, x1, y1, x2, y2, color, width, filled) {
+    if (type === "straight-line") {
+        drawLineNoPut(context, imageData, x1, y1, x2, y2, color, width);
+        return;
+    }
+
+    const left = Math.floor(Math.min(x1, x2));
+    const right = Math.floor(Math.max(x1, x2));
+    const top = Math.floor(Math.min(y1, y2));
+    const bottom = Math.floor(Math.max(y1, y2));
+    const halfWidth = Math.floor(width / 2);
+
+    if (type === "rectangle") {
+        for (let y = top - halfWidth; y <= bottom + halfWidth; y++) {
+            if (filled || y < top + halfWidth || y > bottom - halfWidth) {
+                drawSpan(imageData, y, left - halfWidth, right + halfWidth, color);
+            } else {
+                drawSpan(imageData, y, left - halfWidth, left + halfWidth, color);
+                drawSpan(imageData, y, right - halfWidth, right + halfWidth, color);
+            }
+        }
+    } else if (type === "ellipse") {
+        const centerX = (left + right) / 2;
+        const centerY = (top + bottom) / 2;
+        const outerRadiusX = (right - left) / 2 + halfWidth;
+        const outerRadiusY = (bottom - top) / 2 + halfWidth;
+        const innerRadiusX = outerRadiusX - 2 * halfWidth;
+        const innerRadiusY = outerRadiusY - 2 * halfWidth;
+
+        for (let y = Math.floor(centerY - outerRadiusY); y <= Math.ceil(centerY + outerRadiusY); y++) {
+            const distanceY = y - centerY;
+            const outerHalf = ellipseHalfSpan(outerRadiusX, outerRadiusY, distanceY);
+            if (outerHalf < 0) {
+                continue;
+            }
+
+            const innerHalf = filled || innerRadiusX <= 0 ? -1 : ellipseHalfSpan(innerRadiusX, innerRadiusY, distanceY);
+            if (innerHalf < 0) {
+                drawSpan(imageData, y, centerX - outerHalf, centerX + outerHalf, color);
+            } else {
+                drawSpan(imageData, y, centerX - outerHalf, centerX - innerHalf, color);
+                drawSpan(imageData, y, centerX + innerHalf, centerX + outerHalf, color);
+            }
+        }
+    }
+}
+
+// ellipseHalfSpan returns half the width of the ellipse at the given
+// vertical distance from its center or -1 if outside of the ellipse.
+function ellipseHalfSpan(radiusX, radiusY, distanceY) {
+    if (radiusY <= 0 || Math.abs(distanceY) > radiusY) {
+        return -1;
+    }
+    return radiusX * Math.sqrt(1 - Math.pow(distanceY / radiusY, 2));
+}
+
+function drawSpan(imageData, y, fromX, toX, color) {
+    if (y < 0 || y >= imageData.height) {
+        return;
+    }
+
+    const from = Math.max(0, Math.round(fromX));
+    const to = Math.min(imageData.width - 1, Math.round(toX));
+    for (let x = from; x <= to; x++) {
+        setPixel(imageData, x, y, color);
+    }
+}
+
 function drawBresenhamLine(imageData, x1, y1, x2, y2, color) {
     const dx = Math.abs(x2 - x1);
     const dy = Math.abs(y2 - y1);
@@ -227,7 +297,7 @@ function hexStringToRgbColorObject(hexString) {
     return { r: parseInt(match[1], 16), g: parseInt(match[2], 16), b: parseInt(match[3], 16) };
 }
 
-const colorMap = [
+let colorMap = [
     { hex: '#ffffff', rgb: hexStringToRgbColorObject('#ffffff') },
     { hex: '#c1c1c1', rgb: hexStringToRgbColorObject('#c1c1c1') },
     { hex: '#ef130b', rgb: hexStringToRgbColorObject('#ef130b') },
@@ -256,6 +326,11 @@ const colorMap = [
     { hex: '#d1a3a4', rgb: hexStringToRgbColorObject('#d1a3a4') }
 ];
 
+// setPalette replaces the default colors with a custom palette of hex colors.
+function setPalette(palette) {
+    colorMap = palette.map((hex) => ({ hex: hex, rgb: hexStringToRgbColorObject(hex) }));
+}
+
 function indexToHexColor(index) {
     return colorMap[index].hex;
 }
@@ -46,6 +46,25 @@ func Test_templateErrorPage(t *testing.T) {
 	}
 }
 
+func Test_templatePasswordPage(t *testing.T) {
+	t.Parallel()
+
+	var buffer bytes.Buffer
+	err := pageTemplates.ExecuteTemplate(&buffer,
+		"password-page", &passwordPageData{
+			BasePageConfig: &BasePageConfig{
+				checksums: make(map[string]string),
+			},
+			LobbyID:       "TEST",
+			WrongPassword: true,
+			Translation:   translations.DefaultTranslation,
+			Locale:        "en-US",
+		})
+	if err != nil {
+		t.Errorf("Error templating: %s", err)
+	}
+}
+
 func Test_templateIndexPage(t *testing.T) {
 	t.Parallel()
 
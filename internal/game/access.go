@@ -0,0 +1,178 @@
+package game
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"time"
+)
+
+//
+// This file contains the logic for restricting who can join a lobby. Players
+// that have already joined, don't need to authenticate again, as they are
+// identified via their usersession.
+//
+
+// MaxPasswordLength is the maximum amount of characters a lobby password
+// can consist of.
+const MaxPasswordLength = 64
+
+const (
+	passwordSaltLength     = 16
+	passwordHashLength     = 32
+	passwordHashIterations = 100_000
+	// maxPasswordAttempts is the amount of passwords each IP may try per
+	// passwordAttemptWindow. Since hashing is slow on purpose, unlimited
+	// attempts would allow keeping the server busy.
+	maxPasswordAttempts   = 5
+	passwordAttemptWindow = time.Minute
+)
+
+// lobbyAccess holds the credentials that allow new players to join a lobby.
+// If no password has been set, anyone can join.
+type lobbyAccess struct {
+	passwordSalt []byte
+	passwordHash []byte
+	// inviteTokens maps each token to the time it expires at. Tokens allow
+	// joining without knowing the password.
+	inviteTokens map[string]time.Time
+	// passwordAttempts maps IPs to their password attempts of the current
+	// window.
+	passwordAttempts map[string]*passwordAttempts
+}
+
+type passwordAttempts struct {
+	windowStart time.Time
+	count       int
+}
+
+// InviteToken allows joining a password protected lobby without knowing the
+// password.
+type InviteToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func hashPassword(password string, salt []byte) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, password, salt, passwordHashIterations, passwordHashLength)
+}
+
+// SetPassword sets the password required for joining the lobby. An empty
+// password removes the protection. Since the password is read without
+// locking, this mustn't be called after the lobby has been made available.
+func (lobby *Lobby) SetPassword(password string) error {
+	if password == "" {
+		lobby.access.passwordSalt = nil
+		lobby.access.passwordHash = nil
+		return nil
+	}
+
+	salt := make([]byte, passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("error generating password salt: %w", err)
+	}
+	hash, err := hashPassword(password, salt)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %w", err)
+	}
+
+	lobby.access.passwordSalt = salt
+	lobby.access.passwordHash = hash
+	return nil
+}
+
+// HasPassword indicates whether new players have to supply a password or an
+// invite token in order to join.
+func (lobby *Lobby) HasPassword() bool {
+	return len(lobby.access.passwordHash) > 0
+}
+
+// CheckPassword checks whether the password grants access to the lobby. If
+// the lobby has no password, any password is accepted. As hashing is slow on
+// purpose, this doesn't require the lobby to be locked.
+func (lobby *Lobby) CheckPassword(password string) bool {
+	if !lobby.HasPassword() {
+		return true
+	}
+	if password == "" {
+		return false
+	}
+
+	hash, err := hashPassword(password, lobby.access.passwordSalt)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(hash, lobby.access.passwordHash) == 1
+}
+
+// AllowPasswordAttempt counts a password attempt of the given IP and
+// reports whether the IP may still try passwords. This has to be called
+// before CheckPassword for players that want to join.
+func (lobby *Lobby) AllowPasswordAttempt(address string) bool {
+	now := time.Now()
+	for otherAddress, attempts := range lobby.access.passwordAttempts {
+		if now.Sub(attempts.windowStart) >= passwordAttemptWindow {
+			delete(lobby.access.passwordAttempts, otherAddress)
+		}
+	}
+	if lobby.access.passwordAttempts == nil {
+		lobby.access.passwordAttempts = make(map[string]*passwordAttempts)
+	}
+
+	attempts, exists := lobby.access.passwordAttempts[address]
+	if !exists {
+		attempts = &passwordAttempts{windowStart: now}
+		lobby.access.passwordAttempts[address] = attempts
+	}
+	attempts.count++
+	return attempts.count <= maxPasswordAttempts
+}
+
+// CreateInviteToken creates a new token that allows joining the lobby until
+// it expires or is revoked.
+func (lobby *Lobby) CreateInviteToken(validity time.Duration) InviteToken {
+	lobby.removeExpiredInviteTokens()
+	if lobby.access.inviteTokens == nil {
+		lobby.access.inviteTokens = make(map[string]time.Time)
+	}
+
+	token := InviteToken{
+		Token:     rand.Text(),
+		ExpiresAt: time.Now().Add(validity),
+	}
+	lobby.access.inviteTokens[token.Token] = token.ExpiresAt
+	return token
+}
+
+// RevokeInviteToken invalidates the given token. The return value indicates
+// whether the token existed.
+func (lobby *Lobby) RevokeInviteToken(token string) bool {
+	lobby.removeExpiredInviteTokens()
+	if _, exists := lobby.access.inviteTokens[token]; !exists {
+		return false
+	}
+
+	delete(lobby.access.inviteTokens, token)
+	return true
+}
+
+// IsInviteTokenValid checks whether the token exists and hasn't expired yet.
+func (lobby *Lobby) IsInviteTokenValid(token string) bool {
+	if token == "" {
+		return false
+	}
+
+	expiresAt, exists := lobby.access.inviteTokens[token]
+	return exists && time.Now().Before(expiresAt)
+}
+
+func (lobby *Lobby) removeExpiredInviteTokens() {
+	now := time.Now()
+	for token, expiresAt := range lobby.access.inviteTokens {
+		if !now.Before(expiresAt) {
+			delete(lobby.access.inviteTokens, token)
+		}
+	}
+}
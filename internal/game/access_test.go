@@ -0,0 +1,80 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_lobbyPassword(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{}
+	require.False(t, lobby.HasPassword())
+	require.True(t, lobby.CheckPassword(""))
+	require.True(t, lobby.CheckPassword("anything"))
+
+	require.NoError(t, lobby.SetPassword("secret"))
+	require.True(t, lobby.HasPassword())
+	require.NotEqual(t, []byte("secret"), lobby.access.passwordHash)
+	require.True(t, lobby.CheckPassword("secret"))
+	require.False(t, lobby.CheckPassword("Secret"))
+	require.False(t, lobby.CheckPassword(""))
+
+	require.NoError(t, lobby.SetPassword(""))
+	require.False(t, lobby.HasPassword())
+	require.True(t, lobby.CheckPassword(""))
+}
+
+func Test_lobbyPasswordSalted(t *testing.T) {
+	t.Parallel()
+
+	first, second := &Lobby{}, &Lobby{}
+	require.NoError(t, first.SetPassword("secret"))
+	require.NoError(t, second.SetPassword("secret"))
+	require.NotEqual(t, first.access.passwordHash, second.access.passwordHash)
+}
+
+func Test_inviteTokens(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{}
+	require.False(t, lobby.IsInviteTokenValid(""))
+	require.False(t, lobby.IsInviteTokenValid("unknown"))
+
+	token := lobby.CreateInviteToken(time.Hour)
+	require.NotEmpty(t, token.Token)
+	require.True(t, lobby.IsInviteTokenValid(token.Token))
+
+	otherToken := lobby.CreateInviteToken(time.Hour)
+	require.NotEqual(t, token.Token, otherToken.Token)
+
+	require.True(t, lobby.RevokeInviteToken(token.Token))
+	require.False(t, lobby.IsInviteTokenValid(token.Token))
+	require.False(t, lobby.RevokeInviteToken(token.Token))
+	require.True(t, lobby.IsInviteTokenValid(otherToken.Token))
+
+	expiredToken := lobby.CreateInviteToken(-time.Second)
+	require.False(t, lobby.IsInviteTokenValid(expiredToken.Token))
+
+	// Expired tokens are cleaned up once new tokens are created.
+	lobby.CreateInviteToken(time.Hour)
+	require.NotContains(t, lobby.access.inviteTokens, expiredToken.Token)
+}
+
+func Test_passwordAttempts(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{}
+	for range maxPasswordAttempts {
+		require.True(t, lobby.AllowPasswordAttempt("127.0.0.1"))
+	}
+	require.False(t, lobby.AllowPasswordAttempt("127.0.0.1"))
+	// Other IPs aren't affected.
+	require.True(t, lobby.AllowPasswordAttempt("127.0.0.2"))
+
+	// Once the window has passed, attempts are allowed again.
+	lobby.access.passwordAttempts["127.0.0.1"].windowStart = time.Now().Add(-passwordAttemptWindow)
+	require.True(t, lobby.AllowPasswordAttempt("127.0.0.1"))
+}
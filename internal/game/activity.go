@@ -0,0 +1,93 @@
+package game
+
+import "time"
+
+//
+// This file contains the logic for detecting players that are connected, but
+// away from keyboard. Idle drawers can end their turn early and players that
+// stay idle for several turns are moved to spectating, so that they don't
+// block the lobby.
+//
+
+// playerActivity keeps track of when a player last did something. Only
+// events caused by the user count as activity, keep-alive events don't.
+type playerActivity struct {
+	lastDraw    time.Time
+	lastMessage time.Time
+	lastInput   time.Time
+	// idleTurns is the amount of consecutive turns without any input.
+	idleTurns int
+}
+
+func (activity *playerActivity) trackInput(now time.Time) {
+	activity.lastInput = now
+}
+
+func (activity *playerActivity) trackDraw(now time.Time) {
+	activity.lastDraw = now
+	activity.lastInput = now
+}
+
+func (activity *playerActivity) trackMessage(now time.Time) {
+	activity.lastMessage = now
+	activity.lastInput = now
+}
+
+// shouldEndEarlyDueToIdleDrawer checks whether none of the drawers has drawn
+// anything within DrawerIdleTimeout seconds after choosing the word.
+func (lobby *Lobby) shouldEndEarlyDueToIdleDrawer() bool {
+	if lobby.DrawerIdleTimeout <= 0 || lobby.CurrentWord == "" {
+		return false
+	}
+
+	if time.Since(lobby.wordChosenTime) < time.Duration(lobby.DrawerIdleTimeout)*time.Second {
+		return false
+	}
+
+	drawers := lobby.Drawers()
+	for _, drawer := range drawers {
+		if !drawer.activity.lastDraw.Before(lobby.wordChosenTime) {
+			return false
+		}
+	}
+
+	return len(drawers) > 0
+}
+
+// idleDuringTurn checks whether the player had no input at all during the
+// current turn.
+func (lobby *Lobby) idleDuringTurn(player *Player) bool {
+	// At the start of the game, there was no previous turn.
+	return !lobby.turnStartTime.IsZero() && player.State != Spectating && player.Connected &&
+		player.activity.lastInput.Before(lobby.turnStartTime)
+}
+
+// reachesIdleLimit checks whether the player will be moved to spectating at
+// the end of the current turn. Such players mustn't be chosen as drawer.
+func (lobby *Lobby) reachesIdleLimit(player *Player) bool {
+	return lobby.IdleTurnsToSpectate > 0 && lobby.idleDuringTurn(player) &&
+		player.activity.idleTurns+1 >= lobby.IdleTurnsToSpectate
+}
+
+// updateIdleTurns counts the turns in which players had no input at all and
+// moves them to spectating once they reach IdleTurnsToSpectate. This has to
+// be called at the end of a turn, before the player states are reset. The
+// return value indicates whether the player has been moved.
+func (lobby *Lobby) updateIdleTurns(player *Player) bool {
+	if !lobby.idleDuringTurn(player) {
+		player.activity.idleTurns = 0
+		return false
+	}
+
+	player.activity.idleTurns++
+	if lobby.IdleTurnsToSpectate <= 0 || player.activity.idleTurns < lobby.IdleTurnsToSpectate {
+		return false
+	}
+
+	player.activity.idleTurns = 0
+	player.State = Spectating
+	// A pending request to stop spectating would instantly undo this.
+	player.SpectateToggleRequested = false
+	lobby.WriteObject(player, EventTypeOnly{Type: EventTypeIdleSpectating})
+	return true
+}
@@ -0,0 +1,288 @@
+package game
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+//
+// This file contains the binary wire format for drawing events. Clients can
+// opt into it during the websocket upgrade. All other events are still sent
+// as JSON text frames, even for clients that use the binary format.
+//
+
+// The first byte of each binary frame determines the type of the event.
+const (
+	binaryEventTypeLine              byte = 1
+	binaryEventTypeFill              byte = 2
+	binaryEventTypeUndo              byte = 3
+	binaryEventTypeClearDrawingBoard byte = 4
+	// binaryEventTypeDrawing is outgoing only and contains the whole drawing.
+	// Its payload is a concatenation of line, fill and shape frames.
+	binaryEventTypeDrawing byte = 5
+	binaryEventTypeRedo    byte = 6
+	// binaryEventTypeStrokeLine is a line that additionally carries the ID of
+	// the stroke it belongs to.
+	binaryEventTypeStrokeLine byte = 7
+	// binaryEventTypeDrawingBatch is outgoing only and has the same payload
+	// as binaryEventTypeDrawing, but has to be drawn on top of the canvas.
+	binaryEventTypeDrawingBatch byte = 8
+	binaryEventTypeRectangle    byte = 9
+	binaryEventTypeEllipse      byte = 10
+	binaryEventTypeStraightLine byte = 11
+)
+
+// binaryShapeFlagFilled is set in the flags byte of filled shapes.
+const binaryShapeFlagFilled byte = 1
+
+const (
+	// binaryLineLength is type + x, y, x2, y2 (int16 each) + color + width.
+	binaryLineLength = 1 + 4*2 + 1 + 1
+	// binaryStrokeLineLength is a line followed by the stroke ID (uint32).
+	binaryStrokeLineLength = binaryLineLength + 4
+	// binaryFillLength is type + x, y (uint16 each) + color.
+	binaryFillLength = 1 + 2*2 + 1
+	// binaryShapeLength is type + x, y, x2, y2 (int16 each) + color + width
+	// + flags.
+	binaryShapeLength = binaryLineLength + 1
+)
+
+var shapeEventTypes = map[byte]string{
+	binaryEventTypeRectangle:    EventTypeRectangle,
+	binaryEventTypeEllipse:      EventTypeEllipse,
+	binaryEventTypeStraightLine: EventTypeStraightLine,
+}
+
+var errEmptyBinaryEvent = errors.New("binary event is empty")
+
+// HandleBinaryEvent decodes a binary drawing event and handles it the same
+// way HandleEvent would handle its JSON counterpart.
+func (lobby *Lobby) HandleBinaryEvent(payload []byte, player *Player) error {
+	if len(payload) == 0 {
+		return errEmptyBinaryEvent
+	}
+
+	lobby.mutex.Lock()
+	defer lobby.mutex.Unlock()
+
+	player.activity.trackInput(time.Now())
+
+	switch payload[0] {
+	case binaryEventTypeLine, binaryEventTypeStrokeLine:
+		if lobby.canDraw(player) {
+			line, err := decodeBinaryLine(payload)
+			if err != nil {
+				return err
+			}
+			lobby.handleLineEvent(line, player)
+		}
+	case binaryEventTypeFill:
+		if lobby.canDraw(player) {
+			fill, err := decodeBinaryFill(payload)
+			if err != nil {
+				return err
+			}
+			lobby.handleFillEvent(fill, player)
+		}
+	case binaryEventTypeRectangle, binaryEventTypeEllipse, binaryEventTypeStraightLine:
+		if lobby.canDraw(player) {
+			shape, err := decodeBinaryShape(payload)
+			if err != nil {
+				return err
+			}
+			lobby.handleShapeEvent(shape, player)
+		}
+	case binaryEventTypeUndo:
+		lobby.handleUndoEvent(player)
+	case binaryEventTypeRedo:
+		lobby.handleRedoEvent(player)
+	case binaryEventTypeClearDrawingBoard:
+		lobby.handleClearDrawingBoardEvent(player)
+	default:
+		return fmt.Errorf("unknown binary event type: %d", payload[0])
+	}
+
+	return nil
+}
+
+func decodeBinaryLine(payload []byte) (*LineEvent, error) {
+	expectedLength := binaryLineLength
+	if payload[0] == binaryEventTypeStrokeLine {
+		expectedLength = binaryStrokeLineLength
+	}
+	if len(payload) != expectedLength {
+		return nil, fmt.Errorf("binary line event must be %d bytes, but was %d", expectedLength, len(payload))
+	}
+
+	line := &LineEvent{Type: EventTypeLine}
+	line.Data.X = int16(binary.LittleEndian.Uint16(payload[1:]))
+	line.Data.Y = int16(binary.LittleEndian.Uint16(payload[3:]))
+	line.Data.X2 = int16(binary.LittleEndian.Uint16(payload[5:]))
+	line.Data.Y2 = int16(binary.LittleEndian.Uint16(payload[7:]))
+	line.Data.Color = payload[9]
+	line.Data.Width = payload[10]
+	if payload[0] == binaryEventTypeStrokeLine {
+		line.Data.StrokeID = binary.LittleEndian.Uint32(payload[11:])
+	}
+	return line, nil
+}
+
+func decodeBinaryFill(payload []byte) (*FillEvent, error) {
+	if len(payload) != binaryFillLength {
+		return nil, fmt.Errorf("binary fill event must be %d bytes, but was %d", binaryFillLength, len(payload))
+	}
+
+	fill := &FillEvent{Type: EventTypeFill}
+	fill.Data = &struct {
+		X     uint16 `json:"x"`
+		Y     uint16 `json:"y"`
+		Color uint8  `json:"color"`
+	}{
+		X:     binary.LittleEndian.Uint16(payload[1:]),
+		Y:     binary.LittleEndian.Uint16(payload[3:]),
+		Color: payload[5],
+	}
+	return fill, nil
+}
+
+func decodeBinaryShape(payload []byte) (*ShapeEvent, error) {
+	if len(payload) != binaryShapeLength {
+		return nil, fmt.Errorf("binary shape event must be %d bytes, but was %d", binaryShapeLength, len(payload))
+	}
+
+	shape := &ShapeEvent{Type: shapeEventTypes[payload[0]]}
+	shape.Data.X = int16(binary.LittleEndian.Uint16(payload[1:]))
+	shape.Data.Y = int16(binary.LittleEndian.Uint16(payload[3:]))
+	shape.Data.X2 = int16(binary.LittleEndian.Uint16(payload[5:]))
+	shape.Data.Y2 = int16(binary.LittleEndian.Uint16(payload[7:]))
+	shape.Data.Color = payload[9]
+	shape.Data.Width = payload[10]
+	shape.Data.Filled = payload[11]&binaryShapeFlagFilled != 0
+	return shape, nil
+}
+
+func appendBinaryLine(buffer []byte, line *LineEvent) []byte {
+	if line.Data.StrokeID != 0 {
+		buffer = append(buffer, binaryEventTypeStrokeLine)
+	} else {
+		buffer = append(buffer, binaryEventTypeLine)
+	}
+	buffer = binary.LittleEndian.AppendUint16(buffer, uint16(line.Data.X))
+	buffer = binary.LittleEndian.AppendUint16(buffer, uint16(line.Data.Y))
+	buffer = binary.LittleEndian.AppendUint16(buffer, uint16(line.Data.X2))
+	buffer = binary.LittleEndian.AppendUint16(buffer, uint16(line.Data.Y2))
+	buffer = append(buffer, line.Data.Color, line.Data.Width)
+	if line.Data.StrokeID != 0 {
+		buffer = binary.LittleEndian.AppendUint32(buffer, line.Data.StrokeID)
+	}
+	return buffer
+}
+
+func appendBinaryFill(buffer []byte, fill *FillEvent) []byte {
+	buffer = append(buffer, binaryEventTypeFill)
+	buffer = binary.LittleEndian.AppendUint16(buffer, fill.Data.X)
+	buffer = binary.LittleEndian.AppendUint16(buffer, fill.Data.Y)
+	return append(buffer, fill.Data.Color)
+}
+
+func appendBinaryShape(buffer []byte, shape *ShapeEvent) ([]byte, bool) {
+	var binaryEventType byte
+	switch shape.Type {
+	case EventTypeRectangle:
+		binaryEventType = binaryEventTypeRectangle
+	case EventTypeEllipse:
+		binaryEventType = binaryEventTypeEllipse
+	case EventTypeStraightLine:
+		binaryEventType = binaryEventTypeStraightLine
+	default:
+		return nil, false
+	}
+
+	var flags byte
+	if shape.Data.Filled {
+		flags |= binaryShapeFlagFilled
+	}
+
+	buffer = append(buffer, binaryEventType)
+	buffer = binary.LittleEndian.AppendUint16(buffer, uint16(shape.Data.X))
+	buffer = binary.LittleEndian.AppendUint16(buffer, uint16(shape.Data.Y))
+	buffer = binary.LittleEndian.AppendUint16(buffer, uint16(shape.Data.X2))
+	buffer = binary.LittleEndian.AppendUint16(buffer, uint16(shape.Data.Y2))
+	return append(buffer, shape.Data.Color, shape.Data.Width, flags), true
+}
+
+// EncodeBinaryEvent returns the binary representation of the given event.
+// If the event has no binary representation, false is returned and the
+// caller should fall back to JSON.
+func EncodeBinaryEvent(data any) ([]byte, bool) {
+	switch event := data.(type) {
+	case *LineEvent:
+		return appendBinaryLine(make([]byte, 0, binaryStrokeLineLength), event), true
+	case *FillEvent:
+		if event.Data == nil {
+			return nil, false
+		}
+		return appendBinaryFill(make([]byte, 0, binaryFillLength), event), true
+	case *ShapeEvent:
+		return appendBinaryShape(make([]byte, 0, binaryShapeLength), event)
+	case EventTypeOnly:
+		return encodeBinaryEventTypeOnly(event.Type)
+	case *EventTypeOnly:
+		return encodeBinaryEventTypeOnly(event.Type)
+	case Event:
+		return encodeBinaryDrawing(&event)
+	case *Event:
+		return encodeBinaryDrawing(event)
+	}
+
+	return nil, false
+}
+
+func encodeBinaryEventTypeOnly(eventType string) ([]byte, bool) {
+	if eventType == EventTypeClearDrawingBoard {
+		return []byte{binaryEventTypeClearDrawingBoard}, true
+	}
+	return nil, false
+}
+
+func encodeBinaryDrawing(event *Event) ([]byte, bool) {
+	var binaryEventType byte
+	switch event.Type {
+	case EventTypeDrawing:
+		binaryEventType = binaryEventTypeDrawing
+	case EventTypeDrawingBatch:
+		binaryEventType = binaryEventTypeDrawingBatch
+	default:
+		return nil, false
+	}
+
+	drawing, ok := event.Data.([]any)
+	if !ok {
+		return nil, false
+	}
+
+	buffer := make([]byte, 1, 1+len(drawing)*binaryLineLength)
+	buffer[0] = binaryEventType
+	for _, drawEvent := range drawing {
+		switch drawEvent := drawEvent.(type) {
+		case *LineEvent:
+			buffer = appendBinaryLine(buffer, drawEvent)
+		case *FillEvent:
+			if drawEvent.Data == nil {
+				return nil, false
+			}
+			buffer = appendBinaryFill(buffer, drawEvent)
+		case *ShapeEvent:
+			var ok bool
+			if buffer, ok = appendBinaryShape(buffer, drawEvent); !ok {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+
+	return buffer, true
+}
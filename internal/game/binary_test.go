@@ -0,0 +1,151 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lxzan/gws"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_binaryLineRoundtrip(t *testing.T) {
+	t.Parallel()
+
+	line := &LineEvent{Type: EventTypeLine}
+	line.Data.X = -20
+	line.Data.Y = 900
+	line.Data.X2 = 1700
+	line.Data.Y2 = -1
+	line.Data.Color = 13
+	line.Data.Width = 24
+
+	encoded, ok := EncodeBinaryEvent(line)
+	require.True(t, ok)
+	require.Len(t, encoded, binaryLineLength)
+
+	decoded, err := decodeBinaryLine(encoded)
+	require.NoError(t, err)
+	require.Equal(t, line, decoded)
+}
+
+func Test_binaryStrokeLineRoundtrip(t *testing.T) {
+	t.Parallel()
+
+	line := &LineEvent{Type: EventTypeLine}
+	line.Data.X2 = 12
+	line.Data.StrokeID = 70000
+
+	encoded, ok := EncodeBinaryEvent(line)
+	require.True(t, ok)
+	require.Len(t, encoded, binaryStrokeLineLength)
+	require.Equal(t, binaryEventTypeStrokeLine, encoded[0])
+
+	decoded, err := decodeBinaryLine(encoded)
+	require.NoError(t, err)
+	require.Equal(t, line, decoded)
+}
+
+func Test_binaryFillRoundtrip(t *testing.T) {
+	t.Parallel()
+
+	fill, err := decodeBinaryFill([]byte{binaryEventTypeFill, 0x40, 0x06, 0x84, 0x03, 7})
+	require.NoError(t, err)
+	require.Equal(t, uint16(1600), fill.Data.X)
+	require.Equal(t, uint16(900), fill.Data.Y)
+	require.Equal(t, uint8(7), fill.Data.Color)
+
+	encoded, ok := EncodeBinaryEvent(fill)
+	require.True(t, ok)
+	require.Equal(t, []byte{binaryEventTypeFill, 0x40, 0x06, 0x84, 0x03, 7}, encoded)
+}
+
+func Test_binaryShapeRoundtrip(t *testing.T) {
+	t.Parallel()
+
+	shape := &ShapeEvent{Type: EventTypeEllipse}
+	shape.Data.X = -5
+	shape.Data.Y = 10
+	shape.Data.X2 = 1605
+	shape.Data.Y2 = 800
+	shape.Data.Color = 3
+	shape.Data.Width = 16
+	shape.Data.Filled = true
+
+	encoded, ok := EncodeBinaryEvent(shape)
+	require.True(t, ok)
+	require.Len(t, encoded, binaryShapeLength)
+	require.Equal(t, binaryEventTypeEllipse, encoded[0])
+
+	decoded, err := decodeBinaryShape(encoded)
+	require.NoError(t, err)
+	require.Equal(t, shape, decoded)
+
+	drawing, ok := EncodeBinaryEvent(&Event{Type: EventTypeDrawing, Data: []any{shape}})
+	require.True(t, ok)
+	require.Equal(t, encoded, drawing[1:])
+}
+
+func Test_binaryInvalidLength(t *testing.T) {
+	t.Parallel()
+
+	_, err := decodeBinaryLine([]byte{binaryEventTypeLine, 1, 2})
+	require.Error(t, err)
+	_, err = decodeBinaryFill([]byte{binaryEventTypeFill})
+	require.Error(t, err)
+	_, err = decodeBinaryShape([]byte{binaryEventTypeRectangle, 1, 2})
+	require.Error(t, err)
+}
+
+func Test_binaryNonDrawingEventFallsBack(t *testing.T) {
+	t.Parallel()
+
+	_, ok := EncodeBinaryEvent(&Event{Type: EventTypeMessage, Data: "hello"})
+	require.False(t, ok)
+	_, ok = EncodeBinaryEvent(EventTypeOnly{Type: EventTypeShutdown})
+	require.False(t, ok)
+
+	encoded, ok := EncodeBinaryEvent(EventTypeOnly{Type: EventTypeClearDrawingBoard})
+	require.True(t, ok)
+	require.Equal(t, []byte{binaryEventTypeClearDrawingBoard}, encoded)
+}
+
+func Test_binaryAndJSONClientsInSameLobby(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  10,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		ScoreCalculation: ChillScoring,
+		words:            []string{"abc", "def", "ghi"},
+	}
+	lobby.WriteObject = noOpWriteObject
+
+	opcodes := make(map[*Player]gws.Opcode)
+	lobby.WritePreparedMessage = func(player *Player, message *gws.Broadcaster) error {
+		opcodes[player] = getUnexportedField(reflect.ValueOf(message).Elem().FieldByName("opcode")).(gws.Opcode)
+		return nil
+	}
+
+	drawer := lobby.JoinPlayer("drawer")
+	drawer.Connected = true
+	lobby.OwnerID = drawer.ID
+	jsonGuesser := lobby.JoinPlayer("json")
+	jsonGuesser.Connected = true
+	binaryGuesser := lobby.JoinPlayer("binary")
+	binaryGuesser.Connected = true
+	binaryGuesser.SetBinaryEncoding(true)
+
+	startLobbyAndChooseWord(t, lobby, drawer)
+
+	line := &LineEvent{Type: EventTypeLine}
+	line.Data.Width = MaxBrushSize
+	encoded, _ := EncodeBinaryEvent(line)
+	require.NoError(t, lobby.HandleBinaryEvent(encoded, drawer))
+
+	require.Len(t, lobby.currentDrawing, 1)
+	require.Equal(t, gws.OpcodeText, opcodes[jsonGuesser])
+	require.Equal(t, gws.OpcodeBinary, opcodes[binaryGuesser])
+}
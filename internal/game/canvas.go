@@ -0,0 +1,89 @@
+package game
+
+// DefaultAspectRatio is used by lobbies that haven't chosen an aspect ratio.
+// It matches DrawingBoardBaseWidth and DrawingBoardBaseHeight.
+const DefaultAspectRatio = "16:9"
+
+// SupportedAspectRatios contains all aspect ratios lobbies can choose from,
+// in the order they should be displayed in.
+var SupportedAspectRatios = []string{"16:9", "4:3", "1:1", "3:4", "9:16"}
+
+// drawingBoardSizes maps the aspect ratios to the internal canvas size. The
+// shorter side is always the same, so that brush sizes feel the same,
+// independent of the aspect ratio.
+var drawingBoardSizes = map[string]DrawingBoardSize{
+	"16:9": {Width: 1600, Height: 900},
+	"4:3":  {Width: 1200, Height: 900},
+	"1:1":  {Width: 900, Height: 900},
+	"3:4":  {Width: 900, Height: 1200},
+	"9:16": {Width: 900, Height: 1600},
+}
+
+// DrawingBoardSize is the internal size of the canvas. All coordinates of
+// draw events refer to this size, no matter how big the canvas is on the
+// client.
+type DrawingBoardSize struct {
+	Width  int
+	Height int
+}
+
+// CanvasSettings define the canvas of a lobby. Unlike EditableLobbySettings,
+// these can't be changed after creating the lobby, as it would break the
+// current drawing.
+type CanvasSettings struct {
+	// AspectRatio is one of SupportedAspectRatios. If empty, the
+	// DefaultAspectRatio is used.
+	AspectRatio string
+	// MinBrushSize and MaxBrushSize limit the width of lines and shapes. If
+	// zero, the global MinBrushSize and MaxBrushSize are used.
+	MinBrushSize uint8
+	MaxBrushSize uint8
+}
+
+// IsSupportedAspectRatio checks whether the given aspect ratio can be used
+// by lobbies.
+func IsSupportedAspectRatio(aspectRatio string) bool {
+	_, supported := drawingBoardSizes[aspectRatio]
+	return supported
+}
+
+// DrawingBoardSize returns the internal canvas size for the aspect ratio of
+// the lobby.
+func (settings *CanvasSettings) DrawingBoardSize() DrawingBoardSize {
+	if size, supported := drawingBoardSizes[settings.AspectRatio]; supported {
+		return size
+	}
+	return drawingBoardSizes[DefaultAspectRatio]
+}
+
+// BrushSizeBounds returns the minimum and maximum brush size of the lobby.
+func (settings *CanvasSettings) BrushSizeBounds() (uint8, uint8) {
+	minBrushSize, maxBrushSize := settings.MinBrushSize, settings.MaxBrushSize
+	if minBrushSize == 0 {
+		minBrushSize = MinBrushSize
+	}
+	if maxBrushSize == 0 {
+		maxBrushSize = MaxBrushSize
+	}
+	return minBrushSize, maxBrushSize
+}
+
+// SuggestedBrushSizes returns four brush sizes, evenly distributed between
+// the minimum and maximum brush size, starting with the smallest.
+func (settings *CanvasSettings) SuggestedBrushSizes() [4]uint8 {
+	minBrushSize, maxBrushSize := settings.BrushSizeBounds()
+	step := float64(maxBrushSize-minBrushSize) / 3
+
+	var sizes [4]uint8
+	for index := range sizes {
+		sizes[index] = minBrushSize + uint8(float64(index)*step+0.5)
+	}
+	return sizes
+}
+
+// clampBrushSize makes sure the width is within the brush bounds of the
+// lobby. This prevents clients from lagging due to too thick lines.
+func (settings *CanvasSettings) clampBrushSize(width uint8) uint8 {
+	minBrushSize, maxBrushSize := settings.BrushSizeBounds()
+	return max(minBrushSize, min(maxBrushSize, width))
+}
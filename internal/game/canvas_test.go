@@ -0,0 +1,51 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_canvasSettingsDefaults(t *testing.T) {
+	t.Parallel()
+
+	var settings CanvasSettings
+	require.Equal(t, DrawingBoardSize{Width: DrawingBoardBaseWidth, Height: DrawingBoardBaseHeight}, settings.DrawingBoardSize())
+	minBrushSize, maxBrushSize := settings.BrushSizeBounds()
+	require.Equal(t, uint8(MinBrushSize), minBrushSize)
+	require.Equal(t, uint8(MaxBrushSize), maxBrushSize)
+	require.Equal(t, [4]uint8{8, 16, 24, 32}, settings.SuggestedBrushSizes())
+}
+
+func Test_canvasSettingsSuggestedBrushSizes(t *testing.T) {
+	t.Parallel()
+
+	settings := CanvasSettings{MinBrushSize: 4, MaxBrushSize: 64}
+	require.Equal(t, [4]uint8{4, 24, 44, 64}, settings.SuggestedBrushSizes())
+
+	settings = CanvasSettings{MinBrushSize: 10, MaxBrushSize: 10}
+	require.Equal(t, [4]uint8{10, 10, 10, 10}, settings.SuggestedBrushSizes())
+}
+
+func Test_canvasSettingsLimits(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{CanvasSettings: CanvasSettings{
+		AspectRatio:  "9:16",
+		MinBrushSize: 4,
+		MaxBrushSize: 12,
+	}}
+	require.Equal(t, DrawingBoardSize{Width: 900, Height: 1600}, lobby.DrawingBoardSize())
+	require.Equal(t, uint8(12), lobby.clampBrushSize(32))
+	require.Equal(t, uint8(4), lobby.clampBrushSize(1))
+
+	fill, err := decodeBinaryFill([]byte{binaryEventTypeFill, 0, 0, 0x40, 0x06, 0})
+	require.NoError(t, err)
+	require.Equal(t, drawingLimitBounds, lobby.checkFillLimits(fill, &Player{}))
+
+	fill.Data.Y = 1599
+	require.Equal(t, drawingLimitNone, lobby.checkFillLimits(fill, &Player{}))
+
+	fill.Data.X = 900
+	require.Equal(t, drawingLimitBounds, lobby.checkFillLimits(fill, &Player{}))
+}
@@ -0,0 +1,174 @@
+package game
+
+import "time"
+
+//
+// This file contains the logic for starting the game automatically once
+// enough players are ready. If a StartCountdown is configured, the game
+// only starts after the countdown has run out and the start condition still
+// holds. Additionally, lobbies can be scheduled to start at a fixed time.
+//
+
+// StartCountdownEvent is sent when the countdown before the first turn of a
+// game has been started.
+type StartCountdownEvent struct {
+	// TimeLeft is the time in milliseconds until the game starts.
+	TimeLeft int `json:"timeLeft"`
+}
+
+// startCountdown is the countdown before the first turn of a game. It is
+// only set while the countdown is running.
+type startCountdown struct {
+	timer *time.Timer
+	// endTime is a UTC unix-timestamp in milliseconds.
+	endTime int64
+}
+
+// updateStartCondition starts the game or the countdown before the game, if
+// enough players are ready. Otherwise a running countdown is cancelled. The
+// return value indicates whether the game has been started.
+func (lobby *Lobby) updateStartCondition() bool {
+	if !lobby.readyToStart() {
+		lobby.cancelStartCountdown()
+		return false
+	}
+
+	if lobby.StartCountdown <= 0 {
+		lobby.startGame()
+		return true
+	}
+
+	if lobby.startCountdown == nil {
+		lobby.beginStartCountdown()
+	}
+	return false
+}
+
+func (lobby *Lobby) beginStartCountdown() {
+	duration := time.Duration(lobby.StartCountdown) * time.Second
+	countdown := &startCountdown{
+		endTime: getTimeAsMillis() + duration.Milliseconds(),
+	}
+	// The lobby is locked at this point, so the timer can't fire before
+	// the countdown has been fully set up.
+	countdown.timer = time.AfterFunc(duration, func() {
+		lobby.mutex.Lock()
+		defer lobby.mutex.Unlock()
+
+		// The countdown has been cancelled or replaced in the meantime.
+		if lobby.startCountdown != countdown {
+			return
+		}
+
+		lobby.startCountdown = nil
+		if lobby.State != Ongoing && lobby.readyToStart() {
+			lobby.startGame()
+		} else {
+			lobby.Broadcast(&EventTypeOnly{Type: EventTypeStartCountdownCancelled})
+		}
+	})
+	lobby.startCountdown = countdown
+
+	lobby.Broadcast(&Event{
+		Type: EventTypeStartCountdown,
+		Data: StartCountdownEvent{TimeLeft: int(duration.Milliseconds())},
+	})
+}
+
+// revalidateStartCountdown cancels a running countdown if the start
+// condition doesn't hold anymore.
+func (lobby *Lobby) revalidateStartCountdown() {
+	if lobby.State != Ongoing && !lobby.readyToStart() {
+		lobby.cancelStartCountdown()
+	}
+}
+
+// cancelStartCountdown stops a running countdown and informs all players.
+// It does nothing if there's no countdown running.
+func (lobby *Lobby) cancelStartCountdown() {
+	if lobby.startCountdown == nil {
+		return
+	}
+
+	lobby.stopStartCountdown()
+	lobby.Broadcast(&EventTypeOnly{Type: EventTypeStartCountdownCancelled})
+}
+
+// stopStartCountdown stops a running countdown without informing anyone.
+func (lobby *Lobby) stopStartCountdown() {
+	if lobby.startCountdown == nil {
+		return
+	}
+
+	lobby.startCountdown.timer.Stop()
+	lobby.startCountdown = nil
+}
+
+// startCountdownTimeLeft returns the milliseconds left until the game starts
+// or 0 if there's no countdown running.
+func (lobby *Lobby) startCountdownTimeLeft() int {
+	if lobby.startCountdown == nil {
+		return 0
+	}
+
+	return max(1, int(lobby.startCountdown.endTime-getTimeAsMillis()))
+}
+
+// ScheduleStart makes the game start automatically at the given time, if at
+// least MinReadyPlayers players, but at least one, are connected by then.
+// Players can still start the game earlier. Since ScheduledStart is read
+// without locking, this mustn't be called after the lobby has been made
+// available.
+func (lobby *Lobby) ScheduleStart(startTime time.Time) {
+	lobby.ScheduledStart = startTime
+	lobby.scheduledStartTimer = time.AfterFunc(time.Until(startTime), func() {
+		lobby.mutex.Lock()
+		defer lobby.mutex.Unlock()
+
+		if lobby.State != Unstarted {
+			return
+		}
+
+		if lobby.activePlayerCount() >= max(1, lobby.MinReadyPlayers) {
+			lobby.startGame()
+		}
+	})
+}
+
+// StopTimers stops the start countdown and the scheduled start, so that they
+// don't fire after the lobby has been removed.
+func (lobby *Lobby) StopTimers() {
+	lobby.mutex.Lock()
+	defer lobby.mutex.Unlock()
+
+	lobby.stopTimers()
+}
+
+func (lobby *Lobby) stopTimers() {
+	lobby.stopStartCountdown()
+	if lobby.scheduledStartTimer != nil {
+		lobby.scheduledStartTimer.Stop()
+		lobby.scheduledStartTimer = nil
+	}
+}
+
+// activePlayerCount counts the connected players that aren't spectating.
+func (lobby *Lobby) activePlayerCount() int {
+	var count int
+	for _, player := range lobby.players {
+		if player.Connected && player.State != Spectating {
+			count++
+		}
+	}
+	return count
+}
+
+// scheduledStartTimeLeft returns the milliseconds left until the scheduled
+// start or 0 if the lobby isn't scheduled or the time has passed already.
+func (lobby *Lobby) scheduledStartTimeLeft() int {
+	if lobby.ScheduledStart.IsZero() {
+		return 0
+	}
+
+	return max(0, int(time.Until(lobby.ScheduledStart).Milliseconds()))
+}
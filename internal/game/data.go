@@ -20,15 +20,62 @@ type roundEndReason string
 const (
 	drawerDisconnected   roundEndReason = "drawer_disconnected"
 	guessersDisconnected roundEndReason = "guessers_disconnected"
+	turnSkipped          roundEndReason = "turn_skipped"
+	drawerIdle           roundEndReason = "drawer_idle"
+	drawerKicked         roundEndReason = "drawer_kicked"
 )
 
+// GameplaySettings are the lobby settings that are chosen when creating the
+// lobby and can't be changed afterwards.
+type GameplaySettings struct {
+	// DrawersPerTurn is the amount of players drawing the same word on the
+	// same canvas. Additional drawers are only chosen if enough players are
+	// left for guessing. 0 is treated as 1.
+	DrawersPerTurn int
+	// SplitDrawerScore causes the drawer score to be split between all
+	// drawers of a turn, instead of every drawer getting the full score.
+	SplitDrawerScore bool
+	// GuessTimeCap is the maximum amount of seconds left after the first
+	// correct guess of a turn. 0 disables the cap.
+	GuessTimeCap int
+	// GuessedPercentageToEnd ends the turn early once the given percentage
+	// of guessers has guessed the word. 0 disables the rule, as the turn
+	// always ends once everyone has guessed.
+	GuessedPercentageToEnd int
+	// DrawOrder decides in which order players draw.
+	DrawOrder DrawOrder
+	// WordRerollsPerTurn is the amount of times the drawer may request a new
+	// choice of words per turn.
+	WordRerollsPerTurn int
+	// DrawerIdleTimeout ends the turn early if none of the drawers has drawn
+	// anything within the given amount of seconds after choosing the word.
+	// 0 disables the timeout.
+	DrawerIdleTimeout int
+	// IdleTurnsToSpectate is the amount of consecutive turns without any
+	// input, after which a player is moved to spectating. 0 disables this.
+	IdleTurnsToSpectate int
+	// MinReadyPlayers is the amount of ready players required for starting
+	// the game, even if not all connected players are ready. 0 means that
+	// all connected players have to be ready.
+	MinReadyPlayers int
+	// StartCountdown is the amount of seconds between enough players being
+	// ready and the game starting. The countdown is cancelled if the start
+	// condition stops holding. 0 starts the game immediately.
+	StartCountdown int
+}
+
 // Lobby represents a game session. It must not be sent via the API, as it
 // exposes gameplay relevant information.
 type Lobby struct {
 	// ID uniquely identified the Lobby.
 	LobbyID string
+	// LobbyCode is a short, human friendly alternative to the LobbyID. It
+	// is assigned once the lobby is added to the state.
+	LobbyCode string
 
 	EditableLobbySettings
+	CanvasSettings
+	GameplaySettings
 
 	// DrawingTimeNew is the new value of the drawing time. If a round is
 	// already ongoing, we can't simply change the drawing time, as it would
@@ -49,9 +96,29 @@ type Lobby struct {
 	// OwnerID references the Player that currently owns the lobby.
 	// Meaning this player has rights to restart or change certain settings.
 	OwnerID uuid.UUID
+	// Permanent lobbies are managed by the server. They have no owner, are
+	// never cleaned up and restart once enough players are ready.
+	Permanent bool
+	// startCountdown is only set while the countdown is running.
+	startCountdown *startCountdown
+	// ScheduledStart is the time at which the game starts automatically. It
+	// is zero if the lobby isn't scheduled. See ScheduleStart.
+	ScheduledStart time.Time
+	// scheduledStartTimer fires at ScheduledStart. It is stopped once the
+	// lobby is removed. See StopTimers.
+	scheduledStartTimer *time.Timer
 	// ScoreCalculation decides how scores for both guessers and drawers are
 	// determined.
 	ScoreCalculation ScoreCalculation
+	// GameMode decides the rules of the lobby. It can't be changed after
+	// creating the lobby.
+	GameMode GameMode
+	// drawOrder is the shuffled order of the players for the shuffled draw
+	// orders. It may contain players that have already been kicked.
+	drawOrder []*Player
+	// drawnThisRound is used by the LowestScoreDrawOrder to keep track of
+	// the players that already had their turn in the current round.
+	drawnThisRound map[*Player]bool
 	// CurrentWord represents the word that was last selected. If no word has
 	// been selected yet or the round is already over, this should be empty.
 	CurrentWord string
@@ -64,35 +131,48 @@ type Lobby struct {
 	// hintCount is the amount of hints that were initially available
 	// for revelation.
 	hintCount int
+	// manualHintsRevealed is the amount of hints the drawer has revealed
+	// themselves during the current turn.
+	manualHintsRevealed int
+	// hintInterval is the time in milliseconds between two hints. It is
+	// recalculated whenever the turn is shortened, so that the remaining
+	// hints are still spread across the remaining time.
+	hintInterval int64
 	// Round is the round that the Lobby is currently in. This is a number
 	// between 0 and Rounds. 0 indicates that it hasn't started yet.
 	Round             int
 	wordChoiceEndTime time.Time
-	preSelectedWord   int
+	// wordChosenTime is the time at which the drawer chose the current word.
+	wordChosenTime time.Time
+	// turnStartTime is the time at which the current turn started. It is
+	// zero before the first turn of a game.
+	turnStartTime   time.Time
+	preSelectedWord int
 	// wordChoice represents the current choice of words present to the drawer.
 	wordChoice []string
-	Wordpack   string
+	// wordRerollsLeft is the amount of times the drawer may still replace
+	// the wordChoice during the current turn.
+	wordRerollsLeft int
+	Wordpack        string
 	// roundEndTime represents the time at which the current round will end.
 	// This is a UTC unix-timestamp in milliseconds.
 	roundEndTime   int64
 	roundEndReason roundEndReason
 
 	timeLeftTicker *time.Ticker
-	// currentDrawing represents the state of the current canvas. The elements
-	// consist of LineEvent and FillEvent. Please do not modify the contents
-	// of this array an only move AppendLine and AppendFill on the respective
-	// lobby object.
-	currentDrawing []any
-
-	// These variables are used to define the ranges of connected drawing events.
-	// For example a line that has been drawn or a fill that has been executed.
-	// Since we can't trust the client to tell us this, we use the time passed
-	// between draw events as an indicator of which draw events make up one line.
-	// An alternative approach could be using the coordinates and see if they are
-	// connected, but that could technically undo a whole drawing.
-
-	lastDrawEvent                 time.Time
-	connectedDrawEventsIndexStack []int
+	// drawingBoard is the canvas shared by all players of the lobby.
+	drawingBoard
+	// telephone is only set once a game in TelephoneMode has been started.
+	telephone *telephoneGame
+
+	// DrawingBatchInterval defines for how long outgoing drawing events are
+	// buffered before being sent as a single batch. If set to 0, each event
+	// is sent directly.
+	DrawingBatchInterval time.Duration
+	// pendingDrawEvents are drawing events that haven't been sent yet. See
+	// queueDrawEvent.
+	pendingDrawEvents       []pendingDrawEvent
+	drawBatchFlushScheduled bool
 
 	lowercaser cases.Caser
 
@@ -100,10 +180,30 @@ type Lobby struct {
 	// it is empty.
 	LastPlayerDisconnectTime *time.Time
 
+	// Tournament is set for lobbies that are part of a tournament. Such
+	// lobbies aren't cleaned up before the tournament has finished.
+	Tournament *Tournament
+
+	// series contains all finished games of the lobby, oldest first.
+	series []*SeriesGame
+	// results contains the detailed results of the last finished game.
+	results *GameResults
+	// pendingResults and pendingTurn are recorded while a game is ongoing.
+	pendingResults *GameResults
+	pendingTurn    *TurnResult
+
+	// access restricts which new players can join the lobby.
+	access lobbyAccess
+
 	mutex sync.Mutex
 
 	IsWordpackRtl bool
 
+	// Palette contains the colors that color indexes in draw events refer
+	// to, as hex strings. The first color is the canvas color. If empty, the
+	// default palette of the official client is used.
+	Palette []string
+
 	WriteObject          func(*Player, any) error
 	WritePreparedMessage func(*Player, *gws.Broadcaster) error
 }
@@ -135,11 +235,39 @@ func (player *Player) SetWebsocket(socket *gws.Conn) {
 	player.ws = socket
 }
 
+// UsesBinaryEncoding indicates whether drawing events should be sent to the
+// player using the binary format instead of JSON.
+func (player *Player) UsesBinaryEncoding() bool {
+	return player.binaryEncoding
+}
+
+// SetBinaryEncoding defines whether the player has negotiated the binary
+// format for drawing events.
+func (player *Player) SetBinaryEncoding(enabled bool) {
+	player.binaryEncoding = enabled
+}
+
 // GetUserSession returns the players current user session.
 func (player *Player) GetUserSession() uuid.UUID {
 	return player.userSession
 }
 
+// GameMode defines the rules of a lobby.
+type GameMode string
+
+const (
+	// ClassicMode is the default mode, where one player draws a word and
+	// everyone else tries to guess it.
+	ClassicMode GameMode = "classic"
+	// WhiteboardMode has no words, rounds or scores. Everyone can draw at
+	// the same time and the drawing is kept for the lifetime of the lobby.
+	WhiteboardMode GameMode = "whiteboard"
+	// TelephoneMode has every player write a prompt, which is then passed
+	// on, alternating between drawing the previous text and describing the
+	// previous drawing.
+	TelephoneMode GameMode = "telephone"
+)
+
 type PlayerState string
 
 const (
@@ -175,22 +303,18 @@ func (lobby *Lobby) GetOwner() *Player {
 }
 
 func (lobby *Lobby) ClearDrawing() {
-	lobby.currentDrawing = make([]any, 0)
-	lobby.connectedDrawEventsIndexStack = nil
-}
-
-// AppendLine adds a line direction to the current drawing. This exists in order
-// to prevent adding arbitrary elements to the drawing, as the backing array is
-// an empty interface type.
-func (lobby *Lobby) AppendLine(line *LineEvent) {
-	lobby.currentDrawing = append(lobby.currentDrawing, line)
+	lobby.drawingBoard.reset()
+	for _, player := range lobby.players {
+		player.drawHistory = drawHistory{}
+	}
 }
 
-// AppendFill adds a fill direction to the current drawing. This exists in order
-// to prevent adding arbitrary elements to the drawing, as the backing array is
-// an empty interface type.
-func (lobby *Lobby) AppendFill(fill *FillEvent) {
-	lobby.currentDrawing = append(lobby.currentDrawing, fill)
+// PaletteSize returns the amount of colors available in this lobby.
+func (lobby *Lobby) PaletteSize() int {
+	if len(lobby.Palette) == 0 {
+		return DefaultPaletteSize
+	}
+	return len(lobby.Palette)
 }
 
 // SanitizeName removes invalid characters from the players name, resolves
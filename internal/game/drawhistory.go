@@ -0,0 +1,163 @@
+package game
+
+import "time"
+
+// drawHistory contains the undo and redo state of a single author. Each
+// author has their own history, since multiple players can draw at the same
+// time in some game modes.
+//
+// Draw events are grouped, so that for example a whole line can be undone
+// at once. Clients can tag lines with a stroke ID to tell us which lines
+// belong together. For clients that don't, we use the time passed between
+// draw events as an indicator of which draw events make up one line. An
+// alternative approach could be using the coordinates and see if they are
+// connected, but that could technically undo a whole drawing.
+type drawHistory struct {
+	lastDrawEvent time.Time
+	lastStrokeID  uint32
+	// lastWasLine indicates whether following lines may be added to the
+	// group of the authors last draw event.
+	lastWasLine bool
+	// groups contains the IDs of the authors draw event groups, oldest first.
+	groups []uint32
+	// redoStack contains the draw event groups removed via undo. It is
+	// cleared as soon as the author draws anything new.
+	redoStack [][]any
+}
+
+// resetStroke causes the next line to start a new group.
+func (history *drawHistory) resetStroke() {
+	history.lastDrawEvent = time.Time{}
+	history.lastStrokeID = 0
+	history.lastWasLine = false
+}
+
+// isNewStroke decides whether the line starts a new group of draw events
+// for undo. If the client has tagged the line with a stroke ID, we trust
+// it, otherwise we fall back to guessing based on the time passed.
+func (history *drawHistory) isNewStroke(line *LineEvent, now time.Time) bool {
+	if len(history.groups) == 0 || !history.lastWasLine {
+		return true
+	}
+
+	if line.Data.StrokeID != 0 {
+		return line.Data.StrokeID != history.lastStrokeID
+	}
+
+	return history.lastStrokeID != 0 || now.Sub(history.lastDrawEvent) > 150*time.Millisecond
+}
+
+// drawingBoard contains a drawing and the grouping information required to
+// undo and redo parts of it per author.
+type drawingBoard struct {
+	// currentDrawing represents the state of the canvas. The elements
+	// consist of LineEvent, FillEvent and ShapeEvent. Please do not modify
+	// the contents of this array an only move AppendLine, AppendFill and
+	// AppendShape.
+	currentDrawing []any
+
+	// drawingGroups contains the group of each element of currentDrawing.
+	// A group is a set of draw events that is undone at once. Each group
+	// belongs to a single author, see drawHistory.
+	drawingGroups    []uint32
+	lastDrawingGroup uint32
+}
+
+func (board *drawingBoard) reset() {
+	board.currentDrawing = make([]any, 0)
+	board.drawingGroups = nil
+}
+
+// AppendLine adds a line direction to the current drawing. This exists in order
+// to prevent adding arbitrary elements to the drawing, as the backing array is
+// an empty interface type.
+func (board *drawingBoard) AppendLine(line *LineEvent) {
+	board.currentDrawing = append(board.currentDrawing, line)
+	board.drawingGroups = append(board.drawingGroups, 0)
+}
+
+// AppendFill adds a fill direction to the current drawing. This exists in order
+// to prevent adding arbitrary elements to the drawing, as the backing array is
+// an empty interface type.
+func (board *drawingBoard) AppendFill(fill *FillEvent) {
+	board.currentDrawing = append(board.currentDrawing, fill)
+	board.drawingGroups = append(board.drawingGroups, 0)
+}
+
+// AppendShape adds a shape to the current drawing. This exists in order
+// to prevent adding arbitrary elements to the drawing, as the backing array is
+// an empty interface type.
+func (board *drawingBoard) AppendShape(shape *ShapeEvent) {
+	board.currentDrawing = append(board.currentDrawing, shape)
+	board.drawingGroups = append(board.drawingGroups, 0)
+}
+
+// groupLastDrawEvent assigns the last element of the drawing to a group of
+// the author. If newGroup is false, the authors latest group is used.
+func (board *drawingBoard) groupLastDrawEvent(history *drawHistory, newGroup bool) {
+	if newGroup || len(history.groups) == 0 {
+		board.lastDrawingGroup++
+		history.groups = append(history.groups, board.lastDrawingGroup)
+	}
+	board.drawingGroups[len(board.drawingGroups)-1] = history.groups[len(history.groups)-1]
+	history.redoStack = nil
+}
+
+// undo removes the authors latest group of draw events from the drawing.
+// Since other players might have drawn in the meantime, the group doesn't
+// necessarily sit at the end of the drawing. The return value indicates
+// whether the drawing has changed.
+func (board *drawingBoard) undo(history *drawHistory) bool {
+	if len(history.groups) == 0 {
+		return false
+	}
+
+	group := history.groups[len(history.groups)-1]
+	history.groups = history.groups[:len(history.groups)-1]
+	// Following lines have to start a new stroke, even if they reuse the ID
+	// of the stroke that has just been undone.
+	history.resetStroke()
+
+	var removed []any
+	kept := 0
+	for index, drawEvent := range board.currentDrawing {
+		if board.drawingGroups[index] == group {
+			removed = append(removed, drawEvent)
+			continue
+		}
+
+		board.currentDrawing[kept] = drawEvent
+		board.drawingGroups[kept] = board.drawingGroups[index]
+		kept++
+	}
+
+	if len(removed) == 0 {
+		return false
+	}
+
+	clear(board.currentDrawing[kept:])
+	board.currentDrawing = board.currentDrawing[:kept]
+	board.drawingGroups = board.drawingGroups[:kept]
+	history.redoStack = append(history.redoStack, removed)
+	return true
+}
+
+// redo adds the authors latest undone group of draw events back on top of
+// the drawing. The return value indicates whether the drawing has changed.
+func (board *drawingBoard) redo(history *drawHistory) bool {
+	if len(history.redoStack) == 0 {
+		return false
+	}
+
+	redo := history.redoStack[len(history.redoStack)-1]
+	history.redoStack = history.redoStack[:len(history.redoStack)-1]
+
+	board.lastDrawingGroup++
+	history.groups = append(history.groups, board.lastDrawingGroup)
+	for range redo {
+		board.drawingGroups = append(board.drawingGroups, board.lastDrawingGroup)
+	}
+	board.currentDrawing = append(board.currentDrawing, redo...)
+	history.resetStroke()
+	return true
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"time"
+)
+
+// pendingDrawEvent is a buffered drawing event, which hasn't been sent to
+// the other players yet.
+type pendingDrawEvent struct {
+	event  any
+	author *Player
+}
+
+// queueDrawEvent forwards a drawing event to all players except its author.
+// If batching is enabled, the event is buffered and sent as part of a single
+// drawing-batch event, once the batch interval has passed. This reduces the
+// amount of frames and therefore syscalls drastically for bigger lobbies.
+func (lobby *Lobby) queueDrawEvent(event any, author *Player) {
+	if lobby.DrawingBatchInterval <= 0 {
+		lobby.broadcastConditional(event, ExcludePlayer(author))
+		return
+	}
+
+	lobby.pendingDrawEvents = append(lobby.pendingDrawEvents, pendingDrawEvent{
+		event:  event,
+		author: author,
+	})
+
+	// Any other broadcast flushes the batch early, in which case the timer
+	// might flush a batch that has been started afterwards. This is fine, as
+	// it can only cause batches to be sent earlier, not later.
+	if !lobby.drawBatchFlushScheduled {
+		lobby.drawBatchFlushScheduled = true
+		time.AfterFunc(lobby.DrawingBatchInterval, func() {
+			lobby.mutex.Lock()
+			defer lobby.mutex.Unlock()
+
+			lobby.drawBatchFlushScheduled = false
+			lobby.flushDrawEvents()
+		})
+	}
+}
+
+// flushDrawEvents sends all buffered drawing events. Each player receives
+// all events that they haven't authored themselves.
+func (lobby *Lobby) flushDrawEvents() {
+	if len(lobby.pendingDrawEvents) == 0 {
+		return
+	}
+
+	pending := lobby.pendingDrawEvents
+	lobby.pendingDrawEvents = nil
+
+	authors := make(map[*Player]bool, 1)
+	for _, drawEvent := range pending {
+		authors[drawEvent.author] = true
+	}
+
+	// Usually there's only one author, so all other players can share the
+	// same message, while the author doesn't receive anything.
+	var shared *preparedMessage
+	for _, player := range lobby.players {
+		var message *preparedMessage
+		if !authors[player] {
+			if shared == nil {
+				shared = newDrawingBatchMessage(pending, nil)
+			}
+			message = shared
+		} else if message = newDrawingBatchMessage(pending, player); message == nil {
+			continue
+		}
+
+		if !lobby.writePrepared(player, message) {
+			return
+		}
+	}
+}
+
+// newDrawingBatchMessage creates a drawing-batch event containing all events
+// not authored by the excluded player. If no events remain, nil is returned.
+func newDrawingBatchMessage(pending []pendingDrawEvent, exclude *Player) *preparedMessage {
+	drawEvents := make([]any, 0, len(pending))
+	for _, drawEvent := range pending {
+		if drawEvent.author != exclude {
+			drawEvents = append(drawEvents, drawEvent.event)
+		}
+	}
+
+	if len(drawEvents) == 0 {
+		return nil
+	}
+
+	return &preparedMessage{data: &Event{Type: EventTypeDrawingBatch, Data: drawEvents}}
+}
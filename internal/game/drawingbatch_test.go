@@ -0,0 +1,66 @@
+package game
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lxzan/gws"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_drawingBatchOrdering(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  10,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		ScoreCalculation: ChillScoring,
+		words:            []string{"abc", "def", "ghi"},
+		// Long enough for the timer to never fire during the test.
+		DrawingBatchInterval: time.Hour,
+	}
+	lobby.WriteObject = noOpWriteObject
+
+	type receivedEvent struct {
+		Type string            `json:"type"`
+		Data []json.RawMessage `json:"data"`
+	}
+	received := make(map[*Player][]receivedEvent)
+	lobby.WritePreparedMessage = func(player *Player, message *gws.Broadcaster) error {
+		data := getUnexportedField(reflect.ValueOf(message).Elem().FieldByName("payload")).([]byte)
+		var event receivedEvent
+		// Only events with array data are relevant, others fail to decode.
+		_ = json.Unmarshal(data, &event)
+		received[player] = append(received[player], event)
+		return nil
+	}
+
+	drawer := lobby.JoinPlayer("drawer")
+	drawer.Connected = true
+	lobby.OwnerID = drawer.ID
+	guesser := lobby.JoinPlayer("guesser")
+	guesser.Connected = true
+	startLobbyAndChooseWord(t, lobby, drawer)
+
+	clear(received)
+	line := []byte(`{"type":"line","data":{"x":1,"y":1,"x2":2,"y2":2,"width":8}}`)
+	for range 3 {
+		require.NoError(t, lobby.HandleEvent(EventTypeLine, line, drawer))
+	}
+	require.Empty(t, received[guesser])
+	require.Len(t, lobby.currentDrawing, 3)
+
+	require.NoError(t, lobby.HandleEvent(EventTypeClearDrawingBoard, nil, drawer))
+	require.Len(t, received[guesser], 2)
+	require.Equal(t, EventTypeDrawingBatch, received[guesser][0].Type)
+	require.Len(t, received[guesser][0].Data, 3)
+	require.Equal(t, EventTypeClearDrawingBoard, received[guesser][1].Type)
+
+	// The author never receives their own events.
+	require.Empty(t, received[drawer])
+}
@@ -0,0 +1,181 @@
+package game
+
+import "math/rand/v2"
+
+//
+// This file contains the logic for deciding who draws next. The players
+// slice is never reordered, as ranks and the player list sent to clients
+// rely on the join order.
+//
+
+// DrawOrder defines in which order the players of a lobby draw.
+type DrawOrder string
+
+const (
+	// JoinDrawOrder lets players draw in the order they joined the lobby.
+	JoinDrawOrder DrawOrder = "join"
+	// ShuffledDrawOrder shuffles the order once at the start of each game.
+	ShuffledDrawOrder DrawOrder = "shuffled"
+	// ReshuffledDrawOrder shuffles the order at the start of each round.
+	ReshuffledDrawOrder DrawOrder = "reshuffled"
+	// LowestScoreDrawOrder lets the player with the lowest score, that
+	// hasn't drawn in the current round yet, draw next.
+	LowestScoreDrawOrder DrawOrder = "lowest_score"
+)
+
+var SupportedDrawOrders = []string{
+	string(JoinDrawOrder),
+	string(ShuffledDrawOrder),
+	string(ReshuffledDrawOrder),
+	string(LowestScoreDrawOrder),
+}
+
+// canBeNextDrawer checks whether the player can be chosen for the next turn.
+func (lobby *Lobby) canBeNextDrawer(player *Player) bool {
+	return player.desiresToDraw() && player.Connected && !lobby.reachesIdleLimit(player)
+}
+
+// shuffleDrawOrder creates a new random order out of all current players.
+func (lobby *Lobby) shuffleDrawOrder() {
+	lobby.drawOrder = make([]*Player, len(lobby.players))
+	copy(lobby.drawOrder, lobby.players)
+	rand.Shuffle(len(lobby.drawOrder), func(a, b int) {
+		lobby.drawOrder[a], lobby.drawOrder[b] = lobby.drawOrder[b], lobby.drawOrder[a]
+	})
+}
+
+// resetDrawOrder has to be called when a new game starts.
+func (lobby *Lobby) resetDrawOrder() {
+	lobby.drawnThisRound = make(map[*Player]bool)
+	lobby.drawOrder = nil
+	if lobby.DrawOrder == ShuffledDrawOrder || lobby.DrawOrder == ReshuffledDrawOrder {
+		lobby.shuffleDrawOrder()
+	}
+}
+
+// orderedPlayers returns all players in the order they draw in. Players that
+// joined after the order has been shuffled draw last.
+func (lobby *Lobby) orderedPlayers() []*Player {
+	if len(lobby.drawOrder) == 0 {
+		return lobby.players
+	}
+
+	ordered := make([]*Player, 0, len(lobby.players))
+	for _, player := range lobby.drawOrder {
+		// Kicked players are still part of the order.
+		if lobby.hasPlayer(player) {
+			ordered = append(ordered, player)
+		}
+	}
+	for _, player := range lobby.players {
+		if !containsPlayer(lobby.drawOrder, player) {
+			ordered = append(ordered, player)
+		}
+	}
+
+	return ordered
+}
+
+func (lobby *Lobby) hasPlayer(player *Player) bool {
+	return containsPlayer(lobby.players, player)
+}
+
+func containsPlayer(players []*Player, player *Player) bool {
+	for _, otherPlayer := range players {
+		if otherPlayer == player {
+			return true
+		}
+	}
+	return false
+}
+
+// determineNextDrawer returns the next person that's supposed to be drawing, but
+// doesn't tell the lobby yet. The boolean signals whether the current round
+// is over.
+func determineNextDrawer(lobby *Lobby) (*Player, bool) {
+	if lobby.DrawOrder == LowestScoreDrawOrder {
+		return lobby.determineLowestScoreDrawer()
+	}
+
+	players := lobby.orderedPlayers()
+	for index, player := range players {
+		if player.State == Drawing && !player.CoDrawing {
+			// If we have someone that's drawing, take the next one
+			for i := index + 1; i < len(players); i++ {
+				nextPlayer := players[i]
+				if !lobby.canBeNextDrawer(nextPlayer) {
+					continue
+				}
+
+				return nextPlayer, false
+			}
+
+			// No player below the current drawer has been found, therefore we
+			// fallback to our default logic at the bottom.
+			break
+		}
+	}
+
+	if lobby.DrawOrder == ReshuffledDrawOrder {
+		lobby.shuffleDrawOrder()
+		players = lobby.drawOrder
+	}
+
+	// We prefer the first connected player and non-spectating.
+	for _, player := range players {
+		if !lobby.canBeNextDrawer(player) {
+			continue
+		}
+		return player, true
+	}
+
+	// If no player is available, we will simply end the game.
+	return nil, true
+}
+
+// determineLowestScoreDrawer chooses the player with the lowest score that
+// hasn't drawn in the current round yet. On equal scores, the join order
+// decides. The chosen player is marked as having drawn.
+func (lobby *Lobby) determineLowestScoreDrawer() (*Player, bool) {
+	if lobby.drawnThisRound == nil {
+		lobby.drawnThisRound = make(map[*Player]bool)
+	}
+
+	// Before the first turn, there's no round to continue.
+	roundOver := lobby.Round == 0
+	nextDrawer := lobby.lowestScoreCandidate(nil)
+	if nextDrawer == nil {
+		roundOver = true
+	}
+
+	if roundOver {
+		clear(lobby.drawnThisRound)
+		// The current drawer shouldn't draw twice in a row, unless there's
+		// no one else.
+		currentDrawer := lobby.Drawer()
+		nextDrawer = lobby.lowestScoreCandidate(currentDrawer)
+		if nextDrawer == nil && currentDrawer != nil {
+			nextDrawer = lobby.lowestScoreCandidate(nil)
+		}
+	}
+
+	if nextDrawer != nil {
+		lobby.drawnThisRound[nextDrawer] = true
+	}
+	return nextDrawer, roundOver
+}
+
+func (lobby *Lobby) lowestScoreCandidate(exclude *Player) *Player {
+	var candidate *Player
+	for _, player := range lobby.players {
+		if player == exclude || lobby.drawnThisRound[player] || !lobby.canBeNextDrawer(player) {
+			continue
+		}
+
+		if candidate == nil || player.Score < candidate.Score {
+			candidate = player
+		}
+	}
+
+	return candidate
+}
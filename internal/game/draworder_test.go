@@ -0,0 +1,125 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createDrawOrderTestLobby(t *testing.T, drawOrder DrawOrder, playerCount int) (*Lobby, []*Player) {
+	t.Helper()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  120,
+			Rounds:       10,
+			WordsPerTurn: 1,
+		},
+		GameplaySettings: GameplaySettings{DrawOrder: drawOrder},
+		ScoreCalculation: ChillScoring,
+		words:            make([]string, 50),
+	}
+	for index := range lobby.words {
+		lobby.words[index] = "abc"
+	}
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	var players []*Player
+	for range playerCount {
+		player := lobby.JoinPlayer("")
+		player.Connected = true
+		players = append(players, player)
+	}
+	lobby.OwnerID = players[0].ID
+
+	return lobby, players
+}
+
+// playRound returns the drawers of all turns of the current round.
+func playRound(lobby *Lobby) []*Player {
+	round := lobby.Round
+	var drawers []*Player
+	for lobby.Round == round {
+		drawers = append(drawers, lobby.Drawer())
+		advanceLobby(lobby)
+	}
+	return drawers
+}
+
+func Test_joinDrawOrder(t *testing.T) {
+	t.Parallel()
+
+	lobby, players := createDrawOrderTestLobby(t, JoinDrawOrder, 4)
+	lobby.startGame()
+
+	require.Equal(t, players, playRound(lobby))
+	require.Equal(t, players, playRound(lobby))
+}
+
+func Test_shuffledDrawOrder(t *testing.T) {
+	t.Parallel()
+
+	lobby, players := createDrawOrderTestLobby(t, ShuffledDrawOrder, 5)
+	lobby.startGame()
+
+	firstRound := playRound(lobby)
+	require.ElementsMatch(t, players, firstRound)
+	require.Equal(t, firstRound, playRound(lobby))
+
+	// The player list itself mustn't be reordered.
+	require.Equal(t, players, lobby.GetPlayers())
+
+	// Players joining later draw last.
+	lateJoiner := lobby.JoinPlayer("late")
+	lateJoiner.Connected = true
+	require.Equal(t, append(firstRound, lateJoiner), playRound(lobby))
+}
+
+func Test_reshuffledDrawOrder(t *testing.T) {
+	t.Parallel()
+
+	lobby, players := createDrawOrderTestLobby(t, ReshuffledDrawOrder, 5)
+	lobby.startGame()
+
+	for range 3 {
+		require.ElementsMatch(t, players, playRound(lobby))
+	}
+	require.Equal(t, players, lobby.GetPlayers())
+}
+
+func Test_lowestScoreDrawOrder(t *testing.T) {
+	t.Parallel()
+
+	lobby, players := createDrawOrderTestLobby(t, LowestScoreDrawOrder, 4)
+	players[0].Score = 300
+	players[1].Score = 100
+	players[2].Score = 200
+	players[3].Score = 100
+	lobby.startGame()
+	// Starting the game resets the scores.
+	require.Equal(t, players[0], lobby.Drawer())
+
+	players[0].Score = 300
+	players[1].Score = 100
+	players[2].Score = 200
+	players[3].Score = 100
+	advanceLobby(lobby)
+	require.Equal(t, players[1], lobby.Drawer())
+	advanceLobby(lobby)
+	require.Equal(t, players[3], lobby.Drawer())
+	advanceLobby(lobby)
+	require.Equal(t, players[2], lobby.Drawer())
+	require.Equal(t, 1, lobby.Round)
+
+	// The last drawer of a round doesn't start the next round, even with
+	// the lowest score.
+	for _, player := range players {
+		player.Score = 500
+	}
+	players[2].Score = 0
+	players[0].Score = 10
+	advanceLobby(lobby)
+	require.Equal(t, 2, lobby.Round)
+	require.Equal(t, players[0], lobby.Drawer())
+}
@@ -0,0 +1,182 @@
+package game
+
+import (
+	"time"
+
+	"github.com/scribble-rs/scribble.rs/internal/metrics"
+)
+
+const (
+	// DefaultPaletteSize is the amount of colors available in the official
+	// client. Color indexes outside of the palette are rejected.
+	DefaultPaletteSize = 26
+	// MinPaletteSize is the minimum size of custom palettes. Since the first
+	// color is the canvas color, a single color wouldn't allow drawing.
+	MinPaletteSize = 2
+	// MaxPaletteSize is the maximum size of custom palettes, as colors are
+	// sent as a single byte index.
+	MaxPaletteSize = 256
+	// MaxDrawEventsPerTurn limits the size of the drawing. Since the drawing
+	// is sent to every player that connects or requests it, an unlimited
+	// drawing could be used to cause lags for every single player.
+	MaxDrawEventsPerTurn = 20000
+	// drawingFullNoticeInterval limits how often a player is told that the
+	// drawing is full, as the client keeps sending draw events regardless.
+	drawingFullNoticeInterval = 10 * time.Second
+	// maxDrawEventsPerSecond is way more than a human could produce, even on
+	// a high refresh rate screen. It only exists to stop flooding.
+	maxDrawEventsPerSecond = 200
+	// maxUndoRedoPerSecond is lower than maxDrawEventsPerSecond, since each
+	// undo and redo causes the whole drawing to be sent to every player.
+	maxUndoRedoPerSecond = 10
+	// drawingBoardMarginFactor defines how far outside of the canvas lines
+	// may go, relative to the canvas size. We can't reject all out of bounds
+	// coordinates, as the client allows dragging lines over the canvas border.
+	drawingBoardMarginFactor = 1
+)
+
+// drawingLimit describes why a drawing event has been dropped. The value is
+// used as a label for metrics.
+type drawingLimit string
+
+const (
+	drawingLimitNone   drawingLimit = ""
+	drawingLimitRate   drawingLimit = "rate"
+	drawingLimitBounds drawingLimit = "bounds"
+	drawingLimitColor  drawingLimit = "color"
+	drawingLimitSize   drawingLimit = "size"
+)
+
+// eventRateLimiter is a simple fixed window rate limiter. It is cheaper than
+// the Ring used for chat messages, which matters since drawing events are
+// sent very frequently.
+type eventRateLimiter struct {
+	windowStart time.Time
+	count       int
+}
+
+func (limiter *eventRateLimiter) allow(now time.Time, limit int) bool {
+	if now.Sub(limiter.windowStart) >= time.Second {
+		limiter.windowStart = now
+		limiter.count = 0
+	}
+
+	limiter.count++
+	return limiter.count <= limit
+}
+
+// trackDrawingLimit reports whether a limit has been hit and tracks it if so.
+func trackDrawingLimit(limit drawingLimit) bool {
+	if limit == drawingLimitNone {
+		return false
+	}
+
+	metrics.TrackDrawingLimitHit(string(limit))
+	return true
+}
+
+func (lobby *Lobby) isWithinDrawingBoard(x, y int16) bool {
+	size := lobby.DrawingBoardSize()
+	marginX := size.Width * drawingBoardMarginFactor
+	marginY := size.Height * drawingBoardMarginFactor
+	return int(x) >= -marginX && int(x) <= size.Width+marginX &&
+		int(y) >= -marginY && int(y) <= size.Height+marginY
+}
+
+// checkDrawEventLimits checks the limits shared by all events that add to
+// the drawing.
+func (lobby *Lobby) checkDrawEventLimits(player *Player, color uint8) drawingLimit {
+	now := time.Now()
+	if !player.drawRateLimiter.allow(now, maxDrawEventsPerSecond) {
+		return drawingLimitRate
+	}
+
+	if board, _ := lobby.activeDrawingBoard(player); len(board.currentDrawing) >= MaxDrawEventsPerTurn {
+		lobby.sendDrawingFullNotice(player, now)
+		return drawingLimitSize
+	}
+
+	if int(color) >= lobby.PaletteSize() {
+		return drawingLimitColor
+	}
+
+	return drawingLimitNone
+}
+
+// sendDrawingFullNotice tells the player why their drawing isn't accepted
+// anymore. Since whiteboards are never reset between turns, they stay full
+// until the owner clears them.
+func (lobby *Lobby) sendDrawingFullNotice(player *Player, now time.Time) {
+	if now.Sub(player.lastDrawingFullNotice) < drawingFullNoticeInterval {
+		return
+	}
+	player.lastDrawingFullNotice = now
+
+	message := "The drawing is full, no further drawing is possible this turn."
+	if lobby.GameMode == WhiteboardMode {
+		message = "The whiteboard is full, no further drawing is possible until the owner clears it."
+	}
+	_ = lobby.WriteObject(player, Event{Type: EventTypeSystemMessage, Data: message})
+}
+
+func (lobby *Lobby) checkLineLimits(line *LineEvent, player *Player) drawingLimit {
+	if limit := lobby.checkDrawEventLimits(player, line.Data.Color); limit != drawingLimitNone {
+		return limit
+	}
+
+	if !lobby.isWithinDrawingBoard(line.Data.X, line.Data.Y) ||
+		!lobby.isWithinDrawingBoard(line.Data.X2, line.Data.Y2) {
+		return drawingLimitBounds
+	}
+
+	return drawingLimitNone
+}
+
+// checkShapeLimits uses the same limits as lines, since shapes are defined
+// by two points as well.
+func (lobby *Lobby) checkShapeLimits(shape *ShapeEvent, player *Player) drawingLimit {
+	if limit := lobby.checkDrawEventLimits(player, shape.Data.Color); limit != drawingLimitNone {
+		return limit
+	}
+
+	if !lobby.isWithinDrawingBoard(shape.Data.X, shape.Data.Y) ||
+		!lobby.isWithinDrawingBoard(shape.Data.X2, shape.Data.Y2) {
+		return drawingLimitBounds
+	}
+
+	return drawingLimitNone
+}
+
+func (lobby *Lobby) checkFillLimits(fill *FillEvent, player *Player) drawingLimit {
+	if fill.Data == nil {
+		return drawingLimitBounds
+	}
+
+	if limit := lobby.checkDrawEventLimits(player, fill.Data.Color); limit != drawingLimitNone {
+		return limit
+	}
+
+	// Unlike lines, fills must always start on the canvas.
+	size := lobby.DrawingBoardSize()
+	if int(fill.Data.X) >= size.Width || int(fill.Data.Y) >= size.Height {
+		return drawingLimitBounds
+	}
+
+	return drawingLimitNone
+}
+
+func checkUndoRedoLimits(player *Player) drawingLimit {
+	if !player.undoRateLimiter.allow(time.Now(), maxUndoRedoPerSecond) {
+		return drawingLimitRate
+	}
+
+	return drawingLimitNone
+}
+
+func (lobby *Lobby) checkClearLimits(player *Player) drawingLimit {
+	if !player.drawRateLimiter.allow(time.Now(), maxDrawEventsPerSecond) {
+		return drawingLimitRate
+	}
+
+	return drawingLimitNone
+}
@@ -0,0 +1,159 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_eventRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	var limiter eventRateLimiter
+	now := time.Now()
+	for range 3 {
+		require.True(t, limiter.allow(now, 3))
+	}
+	require.False(t, limiter.allow(now, 3))
+	require.False(t, limiter.allow(now.Add(999*time.Millisecond), 3))
+
+	// New window
+	require.True(t, limiter.allow(now.Add(time.Second), 3))
+}
+
+func Test_checkLineLimits(t *testing.T) {
+	t.Parallel()
+
+	newLine := func(x, y, x2, y2 int16, color uint8) *LineEvent {
+		line := &LineEvent{Type: EventTypeLine}
+		line.Data.X = x
+		line.Data.Y = y
+		line.Data.X2 = x2
+		line.Data.Y2 = y2
+		line.Data.Color = color
+		return line
+	}
+
+	tests := []struct {
+		name string
+		line *LineEvent
+		want drawingLimit
+	}{
+		{"on canvas", newLine(0, 0, DrawingBoardBaseWidth, DrawingBoardBaseHeight, 0), drawingLimitNone},
+		{"dragged over border", newLine(1590, 10, 1700, -20, 13), drawingLimitNone},
+		{"far out of bounds", newLine(0, 0, 0, 32000, 13), drawingLimitBounds},
+		{"negative far out of bounds", newLine(-32000, 0, 0, 0, 13), drawingLimitBounds},
+		{"last palette color", newLine(0, 0, 0, 0, DefaultPaletteSize-1), drawingLimitNone},
+		{"color out of palette", newLine(0, 0, 0, 0, DefaultPaletteSize), drawingLimitColor},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			lobby := &Lobby{}
+			require.Equal(t, testCase.want, lobby.checkLineLimits(testCase.line, &Player{}))
+		})
+	}
+}
+
+func Test_checkLineLimitsCustomPalette(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{Palette: []string{"#ffffff", "#000000", "#ff0000"}}
+	line := &LineEvent{Type: EventTypeLine}
+	line.Data.Color = 2
+	require.Equal(t, drawingLimitNone, lobby.checkLineLimits(line, &Player{}))
+
+	line.Data.Color = 3
+	require.Equal(t, drawingLimitColor, lobby.checkLineLimits(line, &Player{}))
+}
+
+func Test_checkFillLimits(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{}
+	fill, err := decodeBinaryFill([]byte{binaryEventTypeFill, 0x40, 0x06, 0, 0, 0})
+	require.NoError(t, err)
+	require.Equal(t, drawingLimitBounds, lobby.checkFillLimits(fill, &Player{}))
+
+	fill.Data.X = DrawingBoardBaseWidth - 1
+	require.Equal(t, drawingLimitNone, lobby.checkFillLimits(fill, &Player{}))
+
+	require.Equal(t, drawingLimitBounds, lobby.checkFillLimits(&FillEvent{}, &Player{}))
+}
+
+func Test_drawingLimits(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  10,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		ScoreCalculation: ChillScoring,
+		words:            []string{"abc", "def", "ghi"},
+	}
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	drawer := lobby.JoinPlayer("drawer")
+	drawer.Connected = true
+	lobby.OwnerID = drawer.ID
+	startLobbyAndChooseWord(t, lobby, drawer)
+
+	line := []byte(`{"type":"line","data":{"x":1,"y":1,"x2":2,"y2":2,"width":8}}`)
+	for range maxDrawEventsPerSecond * 2 {
+		require.NoError(t, lobby.HandleEvent(EventTypeLine, line, drawer))
+	}
+	require.Len(t, lobby.currentDrawing, maxDrawEventsPerSecond)
+
+	// Pretend the drawing is full, even if the ratelimit hasn't been hit.
+	drawer.drawRateLimiter = eventRateLimiter{}
+	lobby.currentDrawing = make([]any, MaxDrawEventsPerTurn)
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line, drawer))
+	require.Len(t, lobby.currentDrawing, MaxDrawEventsPerTurn)
+}
+
+func Test_whiteboardDrawingLimit(t *testing.T) {
+	t.Parallel()
+
+	owner, lobby, err := CreateLobby("", "owner", "english", &EditableLobbySettings{
+		DrawingTime:       120,
+		Rounds:            4,
+		MaxPlayers:        4,
+		ClientsPerIPLimit: 2,
+		WordsPerTurn:      3,
+	}, nil, ChillScoring, WhiteboardMode)
+	require.NoError(t, err)
+	var notices int
+	lobby.WriteObject = func(_ *Player, object any) error {
+		if event, ok := object.(Event); ok && event.Type == EventTypeSystemMessage {
+			notices++
+		}
+		return nil
+	}
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+	guest := lobby.JoinPlayer("guest")
+	guest.Connected = true
+
+	for len(lobby.currentDrawing) < MaxDrawEventsPerTurn {
+		lobby.AppendLine(&LineEvent{})
+	}
+
+	// The whiteboard persists, so a full whiteboard rejects any further
+	// drawing. The player is told about it once, instead of on every event.
+	line := []byte(`{"type":"line","data":{"x":1,"y":1,"x2":2,"y2":2,"width":8}}`)
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line, guest))
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line, guest))
+	require.Len(t, lobby.currentDrawing, MaxDrawEventsPerTurn)
+	require.Equal(t, 1, notices)
+
+	// Only the owner may clear the board.
+	owner.Connected = true
+	require.NoError(t, lobby.HandleEvent(EventTypeClearDrawingBoard, nil, guest))
+	require.NotEmpty(t, lobby.currentDrawing)
+	require.NoError(t, lobby.HandleEvent(EventTypeClearDrawingBoard, nil, owner))
+	require.Empty(t, lobby.currentDrawing)
+}
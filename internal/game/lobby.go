@@ -25,6 +25,12 @@ var SupportedScoreCalculations = []string{
 	"competitive",
 }
 
+var SupportedGameModes = []string{
+	string(ClassicMode),
+	string(WhiteboardMode),
+	string(TelephoneMode),
+}
+
 var SupportedLanguages = map[string]string{
 	"custom":     "Custom words only",
 	"english_gb": "English (GB)",
@@ -41,6 +47,8 @@ var SupportedLanguages = map[string]string{
 	"persian":    "Persian",
 }
 
+// These are the defaults for lobbies that don't define their own
+// CanvasSettings.
 const (
 	DrawingBoardBaseWidth  = 1600
 	DrawingBoardBaseHeight = 900
@@ -64,6 +72,21 @@ type SettingBounds struct {
 	// can be configured now.
 	MaxWordsPerTurn int `json:"maxWordsPerTurn" env:"MAX_WORDS_PER_TURN"`
 	MinWordsPerTurn int `json:"minWordsPerTurn" env:"MIN_WORDS_PER_TURN"`
+	// MinMinBrushSize and MaxMaxBrushSize bound both the minimum and the
+	// maximum brush size a lobby can choose.
+	MinMinBrushSize int `json:"minMinBrushSize" env:"MIN_MIN_BRUSH_SIZE"`
+	MaxMaxBrushSize int `json:"maxMaxBrushSize" env:"MAX_MAX_BRUSH_SIZE"`
+	// MaxDrawersPerTurn limits the amount of players drawing cooperatively.
+	MaxDrawersPerTurn int `json:"maxDrawersPerTurn" env:"MAX_DRAWERS_PER_TURN"`
+	// MaxWordRerollsPerTurn limits how often the drawer can replace the
+	// words to choose from.
+	MaxWordRerollsPerTurn int `json:"maxWordRerollsPerTurn" env:"MAX_WORD_REROLLS_PER_TURN"`
+	// MaxIdleTurns limits the amount of idle turns after which
+	// players are moved to spectating.
+	MaxIdleTurns int `json:"maxIdleTurns" env:"MAX_IDLE_TURNS"`
+	// MaxStartCountdown limits the amount of seconds between enough players
+	// being ready and the game starting.
+	MaxStartCountdown int `json:"maxStartCountdown" env:"MAX_START_COUNTDOWN"`
 }
 
 func (lobby *Lobby) HandleEvent(eventType string, payload []byte, player *Player) error {
@@ -77,15 +100,22 @@ func (lobby *Lobby) HandleEvent(eventType string, payload []byte, player *Player
 	lobby.mutex.Lock()
 	defer lobby.mutex.Unlock()
 
+	// The drawing is requested automatically by the client, so it doesn't
+	// indicate that the player is actually there.
+	if eventType != EventTypeRequestDrawing {
+		player.activity.trackInput(time.Now())
+	}
+
 	// For all followup unmarshalling of the already unmarshalled Event, we
 	// use mapstructure instead. It's cheaper in terms of CPU usage and
 	// memory usage. There are benchmarks to prove this in json_test.go.
 
 	if eventType == EventTypeToggleSpectate {
 		player.SpectateToggleRequested = !player.SpectateToggleRequested
-		if player.SpectateToggleRequested && lobby.State != Ongoing {
+		// Whiteboards have no turns, so we have to apply the state instantly.
+		if player.SpectateToggleRequested && (lobby.State != Ongoing || lobby.GameMode == WhiteboardMode) {
 			if player.State == Spectating {
-				player.State = Standby
+				player.State = lobby.initialPlayerState()
 			} else {
 				player.State = Spectating
 			}
@@ -93,6 +123,7 @@ func (lobby *Lobby) HandleEvent(eventType string, payload []byte, player *Player
 			// Since we apply the state instantly, we reset it instantly as
 			// well.
 			player.SpectateToggleRequested = false
+			lobby.revalidateStartCountdown()
 		}
 
 		lobby.Broadcast(&Event{Type: EventTypeUpdatePlayers, Data: lobby.players})
@@ -110,24 +141,7 @@ func (lobby *Lobby) HandleEvent(eventType string, payload []byte, player *Player
 				return fmt.Errorf("error decoding data: %w", err)
 			}
 
-			// In case the line is too big, we overwrite the data of the event.
-			// This will prevent clients from lagging due to too thick lines.
-			if line.Data.Width > MaxBrushSize {
-				line.Data.Width = MaxBrushSize
-			} else if line.Data.Width < MinBrushSize {
-				line.Data.Width = MinBrushSize
-			}
-
-			now := time.Now()
-			if now.Sub(lobby.lastDrawEvent) > 150*time.Millisecond || lobby.wasLastDrawEventFill() {
-				lobby.connectedDrawEventsIndexStack = append(lobby.connectedDrawEventsIndexStack, len(lobby.currentDrawing))
-			}
-			lobby.lastDrawEvent = now
-
-			lobby.AppendLine(&line)
-
-			// We directly forward the event, as it seems to be valid.
-			lobby.broadcastConditional(&line, ExcludePlayer(player))
+			lobby.handleLineEvent(&line, player)
 		}
 	} else if eventType == EventTypeFill {
 		if lobby.canDraw(player) {
@@ -136,40 +150,54 @@ func (lobby *Lobby) HandleEvent(eventType string, payload []byte, player *Player
 				return fmt.Errorf("error decoding data: %w", err)
 			}
 
-			lobby.connectedDrawEventsIndexStack = append(lobby.connectedDrawEventsIndexStack, len(lobby.currentDrawing))
-			lobby.lastDrawEvent = time.Now()
-
-			lobby.AppendFill(&fill)
+			lobby.handleFillEvent(&fill, player)
+		}
+	} else if eventType == EventTypeRectangle || eventType == EventTypeEllipse || eventType == EventTypeStraightLine {
+		if lobby.canDraw(player) {
+			var shape ShapeEvent
+			if err := json.Unmarshal(payload, &shape); err != nil {
+				return fmt.Errorf("error decoding data: %w", err)
+			}
 
-			// We directly forward the event, as it seems to be valid.
-			lobby.broadcastConditional(&fill, ExcludePlayer(player))
+			// The type is used for replaying the drawing, so we can't trust
+			// the type inside of the payload.
+			shape.Type = eventType
+			lobby.handleShapeEvent(&shape, player)
 		}
 	} else if eventType == EventTypeClearDrawingBoard {
-		if lobby.canDraw(player) && len(lobby.currentDrawing) > 0 {
-			lobby.ClearDrawing()
-			lobby.broadcastConditional(
-				EventTypeOnly{Type: EventTypeClearDrawingBoard},
-				ExcludePlayer(player))
-		}
+		lobby.handleClearDrawingBoardEvent(player)
 	} else if eventType == EventTypeUndo {
-		if lobby.canDraw(player) && len(lobby.currentDrawing) > 0 && len(lobby.connectedDrawEventsIndexStack) > 0 {
-			undoFrom := lobby.connectedDrawEventsIndexStack[len(lobby.connectedDrawEventsIndexStack)-1]
-			lobby.connectedDrawEventsIndexStack = lobby.connectedDrawEventsIndexStack[:len(lobby.connectedDrawEventsIndexStack)-1]
-			if undoFrom < len(lobby.currentDrawing) {
-				lobby.currentDrawing = lobby.currentDrawing[:undoFrom]
-				lobby.Broadcast(&Event{Type: EventTypeDrawing, Data: lobby.currentDrawing})
-			}
+		lobby.handleUndoEvent(player)
+	} else if eventType == EventTypeRedo {
+		lobby.handleRedoEvent(player)
+	} else if eventType == EventTypeTelephoneSubmit {
+		var submission struct {
+			Data TelephoneSubmission `json:"data"`
+		}
+		if err := json.Unmarshal(payload, &submission); err != nil {
+			return fmt.Errorf("error decoding data: %w", err)
 		}
+
+		lobby.handleTelephoneSubmitEvent(player, &submission.Data)
 	} else if eventType == EventTypeChooseWord {
 		var wordChoice IntDataEvent
 		if err := json.Unmarshal(payload, &wordChoice); err != nil {
 			return fmt.Errorf("error decoding data: %w", err)
 		}
-		if player.State == Drawing {
+		if player.State == Drawing && !player.CoDrawing {
 			if err := lobby.selectWord(wordChoice.Data); err != nil {
 				return err
 			}
 		}
+	} else if eventType == EventTypeRerollWords {
+		lobby.handleRerollWordsEvent(player)
+	} else if eventType == EventTypeRevealHint {
+		var hintIndex IntDataEvent
+		if err := json.Unmarshal(payload, &hintIndex); err != nil {
+			return fmt.Errorf("error decoding data: %w", err)
+		}
+
+		lobby.handleRevealHintEvent(player, hintIndex.Data)
 	} else if eventType == EventTypeKickVote {
 		var kickEvent StringDataEvent
 		if err := json.Unmarshal(payload, &kickEvent); err != nil {
@@ -182,6 +210,8 @@ func (lobby *Lobby) HandleEvent(eventType string, payload []byte, player *Player
 		}
 
 		handleKickVoteEvent(lobby, player, toKickID)
+	} else if eventType == EventTypeSkipVote {
+		handleSkipVoteEvent(lobby, player)
 	} else if eventType == EventTypeToggleReadiness {
 		lobby.handleToggleReadinessEvent(player)
 	} else if eventType == EventTypeStart {
@@ -198,14 +228,140 @@ func (lobby *Lobby) HandleEvent(eventType string, payload []byte, player *Player
 	} else if eventType == EventTypeRequestDrawing {
 		// Since the client shouldn't be blocking to wait for the drawing, it's
 		// fine to emit the event if there's no drawing.
-		if len(lobby.currentDrawing) != 0 {
-			_ = lobby.WriteObject(player, Event{Type: EventTypeDrawing, Data: lobby.currentDrawing})
+		board, _ := lobby.activeDrawingBoard(player)
+		if len(board.currentDrawing) != 0 {
+			lobby.flushDrawEvents()
+			_ = lobby.WriteObject(player, Event{Type: EventTypeDrawing, Data: board.currentDrawing})
 		}
 	}
 
 	return nil
 }
 
+// handleLineEvent expects the caller to have checked whether the player is
+// allowed to draw.
+func (lobby *Lobby) handleLineEvent(line *LineEvent, player *Player) {
+	if trackDrawingLimit(lobby.checkLineLimits(line, player)) {
+		return
+	}
+	player.activity.trackDraw(time.Now())
+
+	// In case the line is too big, we overwrite the data of the event.
+	line.Data.Width = lobby.clampBrushSize(line.Data.Width)
+
+	now := time.Now()
+	history := &player.drawHistory
+	board, private := lobby.activeDrawingBoard(player)
+	board.AppendLine(line)
+	board.groupLastDrawEvent(history, history.isNewStroke(line, now))
+	history.lastDrawEvent = now
+	history.lastStrokeID = line.Data.StrokeID
+	history.lastWasLine = true
+
+	// We directly forward the event, as it seems to be valid.
+	if !private {
+		lobby.queueDrawEvent(line, player)
+	}
+}
+
+// handleFillEvent expects the caller to have checked whether the player is
+// allowed to draw.
+func (lobby *Lobby) handleFillEvent(fill *FillEvent, player *Player) {
+	if trackDrawingLimit(lobby.checkFillLimits(fill, player)) {
+		return
+	}
+	player.activity.trackDraw(time.Now())
+
+	board, private := lobby.activeDrawingBoard(player)
+	board.AppendFill(fill)
+	board.groupLastDrawEvent(&player.drawHistory, true)
+	player.drawHistory.resetStroke()
+
+	// We directly forward the event, as it seems to be valid.
+	if !private {
+		lobby.queueDrawEvent(fill, player)
+	}
+}
+
+// handleShapeEvent expects the caller to have checked whether the player is
+// allowed to draw.
+func (lobby *Lobby) handleShapeEvent(shape *ShapeEvent, player *Player) {
+	if trackDrawingLimit(lobby.checkShapeLimits(shape, player)) {
+		return
+	}
+	player.activity.trackDraw(time.Now())
+
+	shape.Data.Width = lobby.clampBrushSize(shape.Data.Width)
+	if shape.Type == EventTypeStraightLine {
+		shape.Data.Filled = false
+	}
+
+	// Each shape can be undone on its own.
+	board, private := lobby.activeDrawingBoard(player)
+	board.AppendShape(shape)
+	board.groupLastDrawEvent(&player.drawHistory, true)
+	player.drawHistory.resetStroke()
+
+	if !private {
+		lobby.queueDrawEvent(shape, player)
+	}
+}
+
+func (lobby *Lobby) handleClearDrawingBoardEvent(player *Player) {
+	board, private := lobby.activeDrawingBoard(player)
+	if lobby.canClear(player) && len(board.currentDrawing) > 0 {
+		if trackDrawingLimit(lobby.checkClearLimits(player)) {
+			return
+		}
+
+		if private {
+			board.reset()
+			player.drawHistory = drawHistory{}
+			return
+		}
+
+		lobby.ClearDrawing()
+		lobby.broadcastConditional(
+			EventTypeOnly{Type: EventTypeClearDrawingBoard},
+			ExcludePlayer(player))
+	}
+}
+
+func (lobby *Lobby) handleUndoEvent(player *Player) {
+	if lobby.canDraw(player) && len(player.drawHistory.groups) > 0 {
+		if trackDrawingLimit(checkUndoRedoLimits(player)) {
+			return
+		}
+
+		if board, _ := lobby.activeDrawingBoard(player); board.undo(&player.drawHistory) {
+			lobby.sendDrawingUpdate(player, board)
+		}
+	}
+}
+
+func (lobby *Lobby) handleRedoEvent(player *Player) {
+	if lobby.canDraw(player) && len(player.drawHistory.redoStack) > 0 {
+		if trackDrawingLimit(checkUndoRedoLimits(player)) {
+			return
+		}
+
+		if board, _ := lobby.activeDrawingBoard(player); board.redo(&player.drawHistory) {
+			lobby.sendDrawingUpdate(player, board)
+		}
+	}
+}
+
+// sendDrawingUpdate sends the whole drawing after undo or redo. Private
+// drawings are only sent back to their author.
+func (lobby *Lobby) sendDrawingUpdate(author *Player, board *drawingBoard) {
+	if board != &lobby.drawingBoard {
+		_ = lobby.WriteObject(author, &Event{Type: EventTypeDrawing, Data: board.currentDrawing})
+		return
+	}
+
+	lobby.Broadcast(&Event{Type: EventTypeDrawing, Data: board.currentDrawing})
+}
+
 func (lobby *Lobby) handleToggleReadinessEvent(player *Player) {
 	if lobby.State != Ongoing && player.State != Spectating {
 		if player.State != Ready {
@@ -214,15 +370,17 @@ func (lobby *Lobby) handleToggleReadinessEvent(player *Player) {
 			player.State = Standby
 		}
 
-		if lobby.readyToStart() {
-			lobby.startGame()
-		} else {
+		if !lobby.updateStartCondition() {
 			lobby.Broadcast(&Event{Type: EventTypeUpdatePlayers, Data: lobby.players})
 		}
 	}
 }
 
 func (lobby *Lobby) readyToStart() bool {
+	if lobby.MinReadyPlayers > 0 {
+		return lobby.readyPlayerCount() >= lobby.MinReadyPlayers
+	}
+
 	// Otherwise the game will start and gameover instantly. This can happen
 	// if a lobby is created and the owner refreshes.
 	var hasConnectedPlayers bool
@@ -241,6 +399,17 @@ func (lobby *Lobby) readyToStart() bool {
 	return hasConnectedPlayers
 }
 
+// readyPlayerCount counts the connected players that are ready to start.
+func (lobby *Lobby) readyPlayerCount() int {
+	var count int
+	for _, otherPlayer := range lobby.players {
+		if otherPlayer.Connected && otherPlayer.State == Ready {
+			count++
+		}
+	}
+	return count
+}
+
 func isRatelimited(sender *Player) bool {
 	if sender.messageTimestamps.size < 5 {
 		return false
@@ -259,6 +428,7 @@ func isRatelimited(sender *Player) bool {
 func handleMessage(message string, sender *Player, lobby *Lobby) {
 	// No matter whether the message is send, we'll make ratelimitting take place.
 	sender.messageTimestamps.Push(time.Now())
+	sender.activity.trackMessage(time.Now())
 
 	// Very long message can cause lags and can therefore be easily abused.
 	// While it is debatable whether a 10000 byte (not character) long
@@ -309,6 +479,7 @@ func handleMessage(message string, sender *Player, lobby *Lobby) {
 		{
 			sender.LastScore = lobby.calculateGuesserScore()
 			sender.Score += sender.LastScore
+			lobby.recordGuess(sender)
 
 			sender.State = Standby
 
@@ -317,9 +488,10 @@ func handleMessage(message string, sender *Player, lobby *Lobby) {
 			lobby.broadcastConditional(&Event{Type: EventTypeCorrectGuess, Data: sender.ID}, ExcludePlayer(sender))
 			_ = lobby.WriteObject(sender, Event{Type: EventTypeCorrectGuessSelf, Data: lobby.wordHintsShown})
 
-			if !lobby.isAnyoneStillGuessing() {
+			if !lobby.isAnyoneStillGuessing() || lobby.enoughPlayersGuessed() {
 				advanceLobby(lobby)
 			} else {
+				lobby.capTimeLeftAfterCorrectGuess()
 				recalculateRanks(lobby)
 				lobby.Broadcast(&Event{Type: EventTypeUpdatePlayers, Data: lobby.players})
 			}
@@ -337,14 +509,6 @@ func handleMessage(message string, sender *Player, lobby *Lobby) {
 	}
 }
 
-func (lobby *Lobby) wasLastDrawEventFill() bool {
-	if len(lobby.currentDrawing) == 0 {
-		return false
-	}
-	_, isFillEvent := lobby.currentDrawing[len(lobby.currentDrawing)-1].(*FillEvent)
-	return isFillEvent
-}
-
 func (lobby *Lobby) isAnyoneStillGuessing() bool {
 	for _, otherPlayer := range lobby.players {
 		if otherPlayer.State == Guessing && otherPlayer.Connected {
@@ -355,6 +519,50 @@ func (lobby *Lobby) isAnyoneStillGuessing() bool {
 	return false
 }
 
+// enoughPlayersGuessed checks whether the percentage of connected guessers
+// that have already guessed the word reaches GuessedPercentageToEnd.
+func (lobby *Lobby) enoughPlayersGuessed() bool {
+	if lobby.GuessedPercentageToEnd <= 0 {
+		return false
+	}
+
+	var guessed, guessing int
+	for _, player := range lobby.players {
+		if !player.Connected {
+			continue
+		}
+
+		// Players that already guessed correctly are on Standby.
+		if player.State == Standby {
+			guessed++
+		} else if player.State == Guessing {
+			guessing++
+		}
+	}
+
+	return guessed*100 >= lobby.GuessedPercentageToEnd*(guessed+guessing)
+}
+
+// capTimeLeftAfterCorrectGuess shortens the turn to GuessTimeCap, unless
+// less time is left anyway.
+func (lobby *Lobby) capTimeLeftAfterCorrectGuess() {
+	if lobby.GuessTimeCap <= 0 {
+		return
+	}
+
+	timeLeft := lobby.roundEndTime - getTimeAsMillis()
+	timeCap := int64(lobby.GuessTimeCap) * 1000
+	if timeLeft <= timeCap {
+		return
+	}
+
+	lobby.roundEndTime = getTimeAsMillis() + timeCap
+	// The remaining hints have to be revealed earlier, otherwise the turn
+	// could end before they are shown.
+	lobby.hintInterval = timeCap / int64(lobby.hintsLeft+1)
+	lobby.Broadcast(&Event{Type: EventTypeUpdateTimeLeft, Data: timeCap})
+}
+
 func ExcludePlayer(toExclude *Player) func(*Player) bool {
 	return func(player *Player) bool {
 		return player != toExclude
@@ -382,40 +590,76 @@ func (lobby *Lobby) broadcastMessage(message string, sender *Player) {
 }
 
 func (lobby *Lobby) Broadcast(data any) {
-	bytes, err := json.Marshal(data)
-	if err != nil {
-		log.Println("error marshalling Broadcast message", err)
-		return
+	lobby.broadcastConditional(data, func(*Player) bool { return true })
+}
+
+func (lobby *Lobby) broadcastConditional(data any, condition func(*Player) bool) {
+	// Buffered drawing events have to be sent first, in order to keep the
+	// order of events intact.
+	lobby.flushDrawEvents()
+	lobby.writeConditional(&preparedMessage{data: data}, condition)
+}
+
+func (lobby *Lobby) writeConditional(message *preparedMessage, condition func(*Player) bool) {
+	for _, player := range lobby.players {
+		if condition(player) && !lobby.writePrepared(player, message) {
+			return
+		}
 	}
+}
 
-	message := gws.NewBroadcaster(gws.OpcodeText, bytes)
-	for _, player := range lobby.GetPlayers() {
-		lobby.WritePreparedMessage(player, message)
+// writePrepared returns false if the message couldn't be encoded, in which
+// case there's no point in trying to send it to any other player.
+func (lobby *Lobby) writePrepared(player *Player, message *preparedMessage) bool {
+	prepared, err := message.forPlayer(player)
+	if err != nil {
+		log.Println("error marshalling broadcast message", err)
+		return false
 	}
+
+	lobby.WritePreparedMessage(player, prepared)
+	return true
 }
 
-func (lobby *Lobby) broadcastConditional(data any, condition func(*Player) bool) {
-	var message *gws.Broadcaster
-	for _, player := range lobby.players {
-		if condition(player) {
-			if message == nil {
-				bytes, err := json.Marshal(data)
-				if err != nil {
-					log.Println("error marshalling broadcastConditional message", err)
-					return
-				}
+// preparedMessage lazily encodes an outgoing event. The messages are created
+// lazily, since the conditional events could potentially not be sent at all
+// and since most lobbies won't contain clients using the binary format. The
+// cost of the nil-checks is much lower than the cost of creating a message.
+type preparedMessage struct {
+	data any
 
-				// Message is created lazily, since the conditional events could
-				// potentially not be sent at all. The cost of the nil-check is
-				// much lower than the cost of creating the message.
-				message = gws.NewBroadcaster(gws.OpcodeText, bytes)
-			}
-			lobby.WritePreparedMessage(player, message)
+	text     *gws.Broadcaster
+	binary   *gws.Broadcaster
+	noBinary bool
+}
+
+func (message *preparedMessage) forPlayer(player *Player) (*gws.Broadcaster, error) {
+	if player.binaryEncoding && !message.noBinary {
+		if message.binary != nil {
+			return message.binary, nil
 		}
+
+		if bytes, ok := EncodeBinaryEvent(message.data); ok {
+			message.binary = gws.NewBroadcaster(gws.OpcodeBinary, bytes)
+			return message.binary, nil
+		}
+		message.noBinary = true
+	}
+
+	if message.text == nil {
+		bytes, err := json.Marshal(message.data)
+		if err != nil {
+			return nil, err
+		}
+		message.text = gws.NewBroadcaster(gws.OpcodeText, bytes)
 	}
+	return message.text, nil
 }
 
 func (lobby *Lobby) startGame() {
+	// The game might be force started during the countdown.
+	lobby.stopStartCountdown()
+
 	// We are reseting each players score, since players could
 	// technically be player a second game after the last one
 	// has already ended.
@@ -426,8 +670,17 @@ func (lobby *Lobby) startGame() {
 		otherPlayer.Rank = 1
 	}
 
+	if lobby.GameMode == TelephoneMode {
+		lobby.startTelephone()
+		return
+	}
+
 	// Cause advanceLobby to start at round 1, starting the game anew.
 	lobby.Round = 0
+	// Players can't have been idle during the time between two games.
+	lobby.turnStartTime = time.Time{}
+	lobby.resetDrawOrder()
+	lobby.beginGameResults()
 
 	advanceLobby(lobby)
 }
@@ -487,6 +740,45 @@ func handleKickVoteEvent(lobby *Lobby, player *Player, toKickID uuid.UUID) {
 	}
 }
 
+// handleSkipVoteEvent allows guessers to skip the current turn, for example
+// if the word is inappropriate or the drawer isn't doing anything.
+func handleSkipVoteEvent(lobby *Lobby, player *Player) {
+	// Only the classic mode has turns that could be skipped.
+	if lobby.State != Ongoing || lobby.GameMode == WhiteboardMode || lobby.GameMode == TelephoneMode {
+		return
+	}
+
+	// The drawers can't skip their own turn and spectators aren't part of
+	// the turn at all.
+	if player.State == Drawing || player.State == Spectating || player.votedForSkip {
+		return
+	}
+
+	player.votedForSkip = true
+	var voteSkipCount int
+	for _, otherPlayer := range lobby.players {
+		if otherPlayer.Connected && otherPlayer.votedForSkip {
+			voteSkipCount++
+		}
+	}
+
+	votesRequired := calculateVotesNeededToSkip(lobby)
+	lobby.Broadcast(&Event{
+		Type: EventTypeSkipVote,
+		Data: &SkipVote{
+			PlayerID:          player.ID,
+			PlayerName:        player.Name,
+			VoteCount:         voteSkipCount,
+			RequiredVoteCount: votesRequired,
+		},
+	})
+
+	if voteSkipCount >= votesRequired {
+		lobby.roundEndReason = turnSkipped
+		advanceLobby(lobby)
+	}
+}
+
 // kickPlayer kicks the given player from the lobby, updating the lobby
 // state and sending all necessary events.
 func kickPlayer(lobby *Lobby, playerToKick *Player, playerToKickIndex int) {
@@ -519,7 +811,12 @@ func kickPlayer(lobby *Lobby, playerToKick *Player, playerToKickIndex int) {
 		}
 	}
 
-	if playerToKick.State == Drawing {
+	if lobby.GameMode == WhiteboardMode || lobby.GameMode == TelephoneMode {
+		// There are no turns that could be affected. The drawings of the
+		// kicked player are kept though and telephone steps simply time out.
+		lobby.players = append(lobby.players[:playerToKickIndex], lobby.players[playerToKickIndex+1:]...)
+		lobby.Broadcast(&Event{Type: EventTypeUpdatePlayers, Data: lobby.players})
+	} else if playerToKick.State == Drawing && !playerToKick.CoDrawing {
 		newDrawer, roundOver := determineNextDrawer(lobby)
 		lobby.players = append(lobby.players[:playerToKickIndex], lobby.players[playerToKickIndex+1:]...)
 		lobby.Broadcast(&EventTypeOnly{Type: EventTypeDrawerKicked})
@@ -531,6 +828,9 @@ func kickPlayer(lobby *Lobby, playerToKick *Player, playerToKickIndex int) {
 			otherPlayer.LastScore = 0
 		}
 
+		// The turn ends with the drawer, even if there are co-drawers left.
+		// They were only assisting, so they don't get any points either.
+		lobby.roundEndReason = drawerKicked
 		advanceLobbyPredefineDrawer(lobby, roundOver, newDrawer)
 	} else {
 		lobby.players = append(lobby.players[:playerToKickIndex], lobby.players[playerToKickIndex+1:]...)
@@ -546,15 +846,29 @@ func kickPlayer(lobby *Lobby, playerToKick *Player, playerToKickIndex int) {
 	}
 }
 
+// Drawer returns the player whose turn it is. In cooperative turns, this
+// is the player choosing the word.
 func (lobby *Lobby) Drawer() *Player {
 	for _, player := range lobby.players {
-		if player.State == Drawing {
+		if player.State == Drawing && !player.CoDrawing {
 			return player
 		}
 	}
 	return nil
 }
 
+// Drawers returns all players drawing in the current turn, including
+// the players assisting the drawer.
+func (lobby *Lobby) Drawers() []*Player {
+	var drawers []*Player
+	for _, player := range lobby.players {
+		if player.State == Drawing {
+			drawers = append(drawers, player)
+		}
+	}
+	return drawers
+}
+
 func calculateVotesNeededToKick(lobby *Lobby) int {
 	connectedPlayerCount := lobby.GetConnectedPlayerCount()
 
@@ -574,6 +888,28 @@ func calculateVotesNeededToKick(lobby *Lobby) int {
 	return (connectedPlayerCount + 1) / 2
 }
 
+// calculateVotesNeededToSkip works like calculateVotesNeededToKick, but only
+// takes players into account that are allowed to vote for skipping.
+func calculateVotesNeededToSkip(lobby *Lobby) int {
+	var voterCount int
+	for _, player := range lobby.players {
+		if player.Connected && player.State != Drawing && player.State != Spectating {
+			voterCount++
+		}
+	}
+
+	// Unlike kicking, a single guesser can skip a turn, as they are the only
+	// one affected by it. Out of two guessers, one alone isn't a majority.
+	switch {
+	case voterCount <= 1:
+		return 1
+	case voterCount == 2:
+		return 2
+	default:
+		return (voterCount + 1) / 2
+	}
+}
+
 func handleNameChangeEvent(caller *Player, lobby *Lobby, name string) {
 	oldName := caller.Name
 	newName := SanitizeName(name)
@@ -611,12 +947,25 @@ func advanceLobbyPredefineDrawer(lobby *Lobby, roundOver bool, newDrawer *Player
 		lobby.timeLeftTicker = nil
 	}
 
-	// The drawer can potentially be null if kicked or the game just started.
-	if drawer := lobby.Drawer(); drawer != nil {
-		newDrawerScore := lobby.calculateDrawerScore()
-		drawer.LastScore = newDrawerScore
-		drawer.Score += newDrawerScore
+	// There can potentially be no drawers if kicked or the game just
+	// started. Since all drawers are ignored for the calculation, the
+	// score only has to be calculated once.
+	if drawers := lobby.Drawers(); len(drawers) > 0 {
+		var newDrawerScore int
+		// If the guessers voted to skip the turn, the drawing clearly wasn't
+		// worth any points. The same goes for turns of kicked drawers.
+		if lobby.roundEndReason != turnSkipped && lobby.roundEndReason != drawerKicked {
+			newDrawerScore = lobby.calculateDrawerScore()
+			if lobby.SplitDrawerScore {
+				newDrawerScore /= len(drawers)
+			}
+		}
+		for _, drawer := range drawers {
+			drawer.LastScore = newDrawerScore
+			drawer.Score += newDrawerScore
+		}
 	}
+	lobby.finishTurnResult()
 
 	// We need this for the next-turn / game-over event, in order to allow the
 	// client to know which word was previously supposed to be guessed.
@@ -635,6 +984,12 @@ func advanceLobbyPredefineDrawer(lobby *Lobby, roundOver bool, newDrawer *Player
 		if otherPlayer.State == Guessing || otherPlayer.State == Spectating {
 			otherPlayer.LastScore = 0
 		}
+		otherPlayer.CoDrawing = false
+		otherPlayer.votedForSkip = false
+
+		if lobby.updateIdleTurns(otherPlayer) {
+			continue
+		}
 
 		if otherPlayer.SpectateToggleRequested {
 			otherPlayer.SpectateToggleRequested = false
@@ -666,6 +1021,11 @@ func advanceLobbyPredefineDrawer(lobby *Lobby, roundOver bool, newDrawer *Player
 		// connected anymore.
 		if lobby.Round == lobby.Rounds || newDrawer == nil {
 			lobby.State = GameOver
+			seriesGame := lobby.recordSeriesGame()
+			lobby.finishGameResults(seriesGame)
+			if lobby.Tournament != nil {
+				lobby.Tournament.handleGameOver(lobby, seriesGame)
+			}
 
 			for _, player := range lobby.players {
 				readyData := generateReadyData(lobby, player)
@@ -692,9 +1052,14 @@ func advanceLobbyPredefineDrawer(lobby *Lobby, roundOver bool, newDrawer *Player
 
 	lobby.ClearDrawing()
 	newDrawer.State = Drawing
+	for _, coDrawer := range lobby.determineCoDrawers(newDrawer) {
+		coDrawer.State = Drawing
+		coDrawer.CoDrawing = true
+	}
 	lobby.State = Ongoing
 	lobby.wordChoice = GetRandomWords(lobby.WordsPerTurn, lobby)
 	lobby.preSelectedWord = rand.IntN(len(lobby.wordChoice))
+	lobby.wordRerollsLeft = lobby.WordRerollsPerTurn
 
 	wordChoiceDuration := 30
 
@@ -710,12 +1075,28 @@ func advanceLobbyPredefineDrawer(lobby *Lobby, roundOver bool, newDrawer *Player
 	})
 
 	lobby.wordChoiceEndTime = time.Now().Add(time.Duration(wordChoiceDuration) * time.Second)
+	lobby.turnStartTime = time.Now()
 	lobby.timeLeftTicker = time.NewTicker(1 * time.Second)
 	go startTurnTimeTicker(lobby, lobby.timeLeftTicker)
 
 	lobby.SendYourTurnEvent(newDrawer)
 }
 
+// handleRerollWordsEvent replaces the words the drawer can choose from. The
+// time for choosing a word isn't extended, as this could otherwise be used to
+// stall the game.
+func (lobby *Lobby) handleRerollWordsEvent(player *Player) {
+	if lobby.State != Ongoing || player.State != Drawing || player.CoDrawing ||
+		lobby.CurrentWord != "" || len(lobby.wordChoice) == 0 || lobby.wordRerollsLeft <= 0 {
+		return
+	}
+
+	lobby.wordRerollsLeft--
+	lobby.wordChoice = GetRandomWords(lobby.WordsPerTurn, lobby)
+	lobby.preSelectedWord = rand.IntN(len(lobby.wordChoice))
+	lobby.SendYourTurnEvent(player)
+}
+
 // advanceLobby will either start the game or jump over to the next turn.
 func advanceLobby(lobby *Lobby) {
 	newDrawer, roundOver := determineNextDrawer(lobby)
@@ -731,38 +1112,42 @@ func (player *Player) desiresToDraw() bool {
 	return !player.SpectateToggleRequested
 }
 
-// determineNextDrawer returns the next person that's supposed to be drawing, but
-// doesn't tell the lobby yet. The boolean signals whether the current round
-// is over.
-func determineNextDrawer(lobby *Lobby) (*Player, bool) {
-	for index, player := range lobby.players {
-		if player.State == Drawing {
-			// If we have someone that's drawing, take the next one
-			for i := index + 1; i < len(lobby.players); i++ {
-				nextPlayer := lobby.players[i]
-				if !nextPlayer.desiresToDraw() || !nextPlayer.Connected {
-					continue
-				}
+// minGuessersForCoDrawers is the amount of guessers that have to be left in
+// order to choose additional drawers. Otherwise cooperative turns would be
+// pretty pointless in smaller lobbies.
+const minGuessersForCoDrawers = 2
 
-				return nextPlayer, false
-			}
-
-			// No player below the current drawer has been found, therefore we
-			// fallback to our default logic at the bottom.
-			break
-		}
+// determineCoDrawers returns the players assisting the given drawer. They are
+// the players following the drawer, wrapping around if required, as that's
+// the most predictable choice. This doesn't affect the turn order.
+func (lobby *Lobby) determineCoDrawers(drawer *Player) []*Player {
+	if lobby.DrawersPerTurn < 2 {
+		return nil
 	}
 
-	// We prefer the first connected player and non-spectating.
+	drawerIndex := -1
+	var candidates []*Player
 	for _, player := range lobby.players {
-		if !player.desiresToDraw() || !player.Connected {
+		if player == drawer {
+			drawerIndex = len(candidates)
 			continue
 		}
-		return player, true
+		// At this point, everyone except for spectators is guessing.
+		if player.Connected && player.State == Guessing {
+			candidates = append(candidates, player)
+		}
 	}
 
-	// If no player is available, we will simply end the game.
-	return nil, true
+	coDrawerCount := min(lobby.DrawersPerTurn-1, len(candidates)-minGuessersForCoDrawers)
+	if drawerIndex == -1 || coDrawerCount <= 0 {
+		return nil
+	}
+
+	coDrawers := make([]*Player, 0, coDrawerCount)
+	for i := range coDrawerCount {
+		coDrawers = append(coDrawers, candidates[(drawerIndex+i)%len(candidates)])
+	}
+	return coDrawers
 }
 
 // startTurnTimeTicker executes a loop that listens to the lobbies
@@ -794,6 +1179,10 @@ func (lobby *Lobby) tickLogic(expectedTicker *time.Ticker) bool {
 		return false
 	}
 
+	if lobby.GameMode == TelephoneMode {
+		return lobby.telephoneTickLogic()
+	}
+
 	if lobby.shouldEndEarlyDueToDisconnectedDrawer() {
 		lobby.roundEndReason = drawerDisconnected
 		advanceLobby(lobby)
@@ -806,6 +1195,12 @@ func (lobby *Lobby) tickLogic(expectedTicker *time.Ticker) bool {
 		return false
 	}
 
+	if lobby.shouldEndEarlyDueToIdleDrawer() {
+		lobby.roundEndReason = drawerIdle
+		advanceLobby(lobby)
+		return false
+	}
+
 	if lobby.CurrentWord == "" {
 		if lobby.wordChoiceEndTime.Before(time.Now()) {
 			lobby.selectWord(lobby.preSelectedWord)
@@ -822,37 +1217,21 @@ func (lobby *Lobby) tickLogic(expectedTicker *time.Ticker) bool {
 	}
 
 	if lobby.hintsLeft > 0 && lobby.wordHints != nil {
-		revealHintEveryXMilliseconds := int64(lobby.DrawingTime * 1000 / (lobby.hintCount + 1))
 		// If you have a drawingtime of 120 seconds and three hints, you
 		// want to reveal a hint every 40 seconds, so that the two hints
 		// are visible for at least a third of the time. //If the word
 		// was chosen at 60 seconds, we'll still reveal one hint
 		// instantly, as the time is already lower than 80.
-		revealHintAtXOrLower := revealHintEveryXMilliseconds * int64(lobby.hintsLeft)
+		revealHintAtXOrLower := lobby.hintInterval * int64(lobby.hintsLeft)
 		timeLeft := lobby.roundEndTime - currentTime
 		if timeLeft <= revealHintAtXOrLower {
-			lobby.hintsLeft--
-
 			// We are trying til we find a yet unshown wordhint. Since we have
 			// thread safety and have already checked that there's a hint
 			// left, this loop can never spin forever.
 			for {
 				randomIndex := rand.Int() % len(lobby.wordHints)
 				if lobby.wordHints[randomIndex].Character == 0 {
-					lobby.wordHints[randomIndex].Character = []rune(lobby.CurrentWord)[randomIndex]
-					lobby.wordHints[randomIndex].Revealed = true
-					lobby.wordHintsShown[randomIndex].Revealed = true
-					wordHintData := &Event{
-						Type: EventTypeUpdateWordHint,
-						Data: lobby.wordHints,
-					}
-					lobby.broadcastConditional(wordHintData, IsAllowedToSeeHints)
-
-					wordHintsShownData := &Event{
-						Type: EventTypeUpdateWordHint,
-						Data: lobby.wordHintsShown,
-					}
-					lobby.broadcastConditional(wordHintsShownData, IsAllowedToSeeRevealedHints)
+					lobby.revealHint(randomIndex)
 					break
 				}
 			}
@@ -862,6 +1241,46 @@ func (lobby *Lobby) tickLogic(expectedTicker *time.Ticker) bool {
 	return true
 }
 
+// handleRevealHintEvent allows the drawer to reveal a specific character
+// instead of waiting for a random one. Each manual hint uses up one of the
+// hints left, so the automatic hints are delayed accordingly.
+func (lobby *Lobby) handleRevealHintEvent(player *Player, index int) {
+	if player.State != Drawing || lobby.CurrentWord == "" || lobby.hintsLeft <= 0 {
+		return
+	}
+
+	// Characters that are always visible, such as spaces, are never hidden
+	// in the first place.
+	if index < 0 || index >= len(lobby.wordHints) || lobby.wordHints[index].Character != 0 {
+		return
+	}
+
+	lobby.manualHintsRevealed++
+	lobby.revealHint(index)
+}
+
+// revealHint reveals the character at the given index to all guessers. The
+// caller has to make sure that there are hints left and that the character
+// hasn't been revealed yet.
+func (lobby *Lobby) revealHint(index int) {
+	lobby.hintsLeft--
+
+	lobby.wordHints[index].Character = []rune(lobby.CurrentWord)[index]
+	lobby.wordHints[index].Revealed = true
+	lobby.wordHintsShown[index].Revealed = true
+	wordHintData := &Event{
+		Type: EventTypeUpdateWordHint,
+		Data: lobby.wordHints,
+	}
+	lobby.broadcastConditional(wordHintData, IsAllowedToSeeHints)
+
+	wordHintsShownData := &Event{
+		Type: EventTypeUpdateWordHint,
+		Data: lobby.wordHintsShown,
+	}
+	lobby.broadcastConditional(wordHintsShownData, IsAllowedToSeeRevealedHints)
+}
+
 func (lobby *Lobby) shouldEndEarlyDueToDisconnectedDrawer() bool {
 	// If a word was already chosen, there might already be a drawing. So we only end
 	// early if the drawing isn't empty.
@@ -869,13 +1288,24 @@ func (lobby *Lobby) shouldEndEarlyDueToDisconnectedDrawer() bool {
 		return false
 	}
 
-	drawer := lobby.Drawer()
-	if drawer != nil && !drawer.Connected && drawer.disconnectTime != nil &&
-		time.Since(*drawer.disconnectTime) >= disconnectGrace {
-		return true
+	// Only the drawer can choose the word, but once it has been chosen,
+	// any of the cooperative drawers can keep drawing.
+	drawers := lobby.Drawers()
+	if lobby.CurrentWord == "" {
+		drawers = nil
+		if drawer := lobby.Drawer(); drawer != nil {
+			drawers = append(drawers, drawer)
+		}
 	}
 
-	return false
+	for _, drawer := range drawers {
+		if drawer.Connected || drawer.disconnectTime == nil ||
+			time.Since(*drawer.disconnectTime) < disconnectGrace {
+			return false
+		}
+	}
+
+	return len(drawers) > 0
 }
 
 func (lobby *Lobby) shouldEndEarlyDueToDisconnectedGuessers() bool {
@@ -962,8 +1392,10 @@ func (lobby *Lobby) selectWord(index int) error {
 	}
 
 	lobby.roundEndTime = getTimeAsMillis() + int64(lobby.DrawingTime)*1000
+	lobby.wordChosenTime = time.Now()
 	lobby.CurrentWord = lobby.wordChoice[index]
 	lobby.wordChoice = nil
+	lobby.beginTurnResult()
 
 	// Depending on how long the word is, a fixed amount of hints
 	// would be too easy or too hard.
@@ -978,6 +1410,8 @@ func (lobby *Lobby) selectWord(index int) error {
 		lobby.hintCount = 3
 	}
 	lobby.hintsLeft = lobby.hintCount
+	lobby.manualHintsRevealed = 0
+	lobby.hintInterval = int64(lobby.DrawingTime * 1000 / (lobby.hintCount + 1))
 
 	// We generate both the "empty" word hints and the hints for the
 	// drawer. Since the length is the same, we do it in one run.
@@ -1039,7 +1473,26 @@ func CreateLobby(
 	settings *EditableLobbySettings,
 	customWords []string,
 	scoringCalculation ScoreCalculation,
+	gameMode GameMode,
 ) (*Player, *Lobby, error) {
+	lobby := NewLobby(desiredLobbyId, chosenLanguage, settings, customWords, scoringCalculation, gameMode)
+
+	player := lobby.JoinPlayer(playerName)
+	lobby.OwnerID = player.ID
+
+	return player, lobby, nil
+}
+
+// NewLobby creates a new lobby without any players and therefore without an
+// owner. If no lobby ID is passed, a random one is generated.
+func NewLobby(
+	desiredLobbyId string,
+	chosenLanguage string,
+	settings *EditableLobbySettings,
+	customWords []string,
+	scoringCalculation ScoreCalculation,
+	gameMode GameMode,
+) *Lobby {
 	if desiredLobbyId == "" {
 		desiredLobbyId = uuid.Must(uuid.NewV4()).String()
 	}
@@ -1047,9 +1500,16 @@ func CreateLobby(
 		LobbyID:               desiredLobbyId,
 		EditableLobbySettings: *settings,
 		CustomWords:           customWords,
-		currentDrawing:        make([]any, 0),
+		drawingBoard:          drawingBoard{currentDrawing: make([]any, 0)},
 		State:                 Unstarted,
 		ScoreCalculation:      scoringCalculation,
+		GameMode:              gameMode,
+	}
+
+	// Whiteboards don't have a game that has to be started, they are
+	// usable right away.
+	if gameMode == WhiteboardMode {
+		lobby.State = Ongoing
 	}
 
 	if len(customWords) > 1 {
@@ -1076,10 +1536,7 @@ func CreateLobby(
 		}
 	}
 
-	player := lobby.JoinPlayer(playerName)
-	lobby.OwnerID = player.ID
-
-	return player, lobby, nil
+	return lobby
 }
 
 // generatePlayerName creates a new playername. A so called petname. It consists
@@ -1103,6 +1560,12 @@ func generateReadyData(lobby *Lobby, player *Player) *ReadyEvent {
 		WordHints:          lobby.GetAvailableWordHints(player),
 		Players:            lobby.players,
 		CurrentDrawing:     lobby.currentDrawing,
+		Palette:            lobby.Palette,
+		GameMode:           lobby.GameMode,
+		MinReadyPlayers:    lobby.MinReadyPlayers,
+		StartCountdown:     lobby.startCountdownTimeLeft(),
+		ScheduledStart:     lobby.scheduledStartTimeLeft(),
+		Series:             lobby.series,
 	}
 
 	if lobby.State != Ongoing {
@@ -1112,6 +1575,10 @@ func generateReadyData(lobby *Lobby, player *Player) *ReadyEvent {
 		ready.TimeLeft = int(lobby.roundEndTime - getTimeAsMillis())
 	}
 
+	if lobby.GameMode == TelephoneMode {
+		lobby.addTelephoneReadyData(ready, player)
+	}
+
 	return ready
 }
 
@@ -1122,20 +1589,28 @@ func (lobby *Lobby) SendYourTurnEvent(player *Player) {
 			TimeLeft:        int(lobby.wordChoiceEndTime.UnixMilli() - getTimeAsMillis()),
 			PreSelectedWord: lobby.preSelectedWord,
 			Words:           lobby.wordChoice,
+			RerollsLeft:     lobby.wordRerollsLeft,
 		},
 	})
 }
 
 func (lobby *Lobby) OnPlayerConnectUnsynchronized(player *Player) {
+	// The ready event already contains all buffered drawing events, so we
+	// flush them before the player counts as connected.
+	lobby.flushDrawEvents()
+
 	player.Connected = true
 	player.hasConnectedOnce = true
+	player.activity.trackInput(time.Now())
+	// A player that isn't ready yet might prevent the game from starting.
+	lobby.revalidateStartCountdown()
 	recalculateRanks(lobby)
 	lobby.WriteObject(player, Event{Type: EventTypeReady, Data: generateReadyData(lobby, player)})
 
 	// This state is reached if the player reconnects before having chosen a word.
 	// This can happen if the player refreshes his browser page or the socket
 	// loses connection and reconnects quickly.
-	if player.State == Drawing && lobby.CurrentWord == "" {
+	if player.State == Drawing && !player.CoDrawing && lobby.CurrentWord == "" {
 		lobby.SendYourTurnEvent(player)
 	}
 
@@ -1173,8 +1648,7 @@ func (lobby *Lobby) OnPlayerDisconnect(player *Player) {
 		// FIXME Should we not set spectators to standby? Currently there's no
 		// indication you are spectating right now.
 		player.State = Standby
-		if lobby.readyToStart() {
-			lobby.startGame()
+		if lobby.updateStartCondition() {
 			// Rank Calculation and sending out player updates happened anyway,
 			// so there's no need to keep going.
 			return
@@ -1213,22 +1687,54 @@ func (lobby *Lobby) JoinPlayer(name string) *Player {
 		messageTimestamps: NewRing[time.Time](5),
 	}
 
+	player.State = lobby.initialPlayerState()
+	lobby.players = append(lobby.players, player)
+
+	return player
+}
+
+func (lobby *Lobby) initialPlayerState() PlayerState {
+	if lobby.GameMode == WhiteboardMode {
+		// On a whiteboard everyone is allowed to draw all the time.
+		return Drawing
+	}
+
+	if lobby.State == Ongoing && lobby.GameMode == TelephoneMode {
+		// Telephone chains can't be extended by new players, so they
+		// have to wait for the next game.
+		return Standby
+	}
+
 	if lobby.State == Ongoing {
 		// Joining an existing game will mark you as a guesser, as someone is
 		// always drawing, given there is no pause-state.
-		player.State = Guessing
-	} else {
-		player.State = Standby
+		return Guessing
 	}
-	lobby.players = append(lobby.players, player)
 
-	return player
+	return Standby
 }
 
 func (lobby *Lobby) canDraw(player *Player) bool {
+	if lobby.GameMode == WhiteboardMode {
+		return player.State == Drawing
+	}
+	if lobby.GameMode == TelephoneMode {
+		return lobby.canDrawTelephone(player)
+	}
+
 	return player.State == Drawing && lobby.CurrentWord != "" && lobby.State == Ongoing
 }
 
+// canClear decides whether the player may clear the drawing board. Since
+// everyone shares the board on whiteboards, only the owner may clear it.
+func (lobby *Lobby) canClear(player *Player) bool {
+	if lobby.GameMode == WhiteboardMode {
+		return player.ID == lobby.OwnerID
+	}
+
+	return lobby.canDraw(player)
+}
+
 // Shutdown sends all players an event, indicating that the lobby
 // will be shut down. The caller of this function should take care of not
 // allowing new connections. Clients should gracefully disconnect.
@@ -1237,6 +1743,7 @@ func (lobby *Lobby) Shutdown() {
 	defer lobby.mutex.Unlock()
 	log.Println("Lobby Shutdown: Mutex acquired")
 
+	lobby.stopTimers()
 	lobby.Broadcast(&EventTypeOnly{Type: EventTypeShutdown})
 }
 
@@ -1323,9 +1830,16 @@ func (s *adjustableScoringAlgorithm) CalculateDrawerScore(lobby *Lobby) int {
 		}
 	}
 
-	if playerCount > 0 {
-		return scoreSum / playerCount
+	if playerCount == 0 {
+		return 0
+	}
+
+	// Hints revealed by the drawer make guessing easier, so the drawer
+	// loses the hint bonus for each of them, just like the guessers.
+	score := scoreSum / playerCount
+	if lobby.hintCount > 0 {
+		score -= lobby.manualHintsRevealed * (int(s.maxHintBonusScore) / lobby.hintCount)
 	}
 
-	return 0
+	return max(score, 0)
 }
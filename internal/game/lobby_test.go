@@ -2,7 +2,9 @@ package game
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 	"unsafe"
@@ -540,7 +542,7 @@ func Test_NoPrematureGameOver(t *testing.T) {
 		CustomWordsPerTurn: 3,
 		ClientsPerIPLimit:  1,
 		WordsPerTurn:       3,
-	}, nil, ChillScoring)
+	}, nil, ChillScoring, ClassicMode)
 	require.NoError(t, err)
 
 	lobby.WriteObject = noOpWriteObject
@@ -563,3 +565,865 @@ func Test_NoPrematureGameOver(t *testing.T) {
 	require.Equal(t, Standby, player.State)
 	require.Equal(t, Unstarted, lobby.State)
 }
+
+func startLobbyAndChooseWord(t *testing.T, lobby *Lobby, drawer *Player) {
+	t.Helper()
+
+	require.NoError(t, lobby.HandleEvent(EventTypeStart, nil, drawer))
+	require.NoError(t, lobby.HandleEvent(EventTypeChooseWord, []byte(`{"data": 0}`), drawer))
+}
+
+func Test_undoRedoStrokeIDs(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  10,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		ScoreCalculation: ChillScoring,
+		words:            []string{"abc", "def", "ghi"},
+	}
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	drawer := lobby.JoinPlayer("drawer")
+	drawer.Connected = true
+	lobby.OwnerID = drawer.ID
+	startLobbyAndChooseWord(t, lobby, drawer)
+
+	line := func(strokeID int) []byte {
+		return fmt.Appendf(nil, `{"type":"line","data":{"x":1,"y":1,"x2":2,"y2":2,"width":8,"strokeId":%d}}`, strokeID)
+	}
+
+	// Two separate strokes in quick succession mustn't be merged.
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line(1), drawer))
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line(1), drawer))
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line(2), drawer))
+	require.Len(t, lobby.currentDrawing, 3)
+
+	require.NoError(t, lobby.HandleEvent(EventTypeUndo, nil, drawer))
+	require.Len(t, lobby.currentDrawing, 2)
+	require.NoError(t, lobby.HandleEvent(EventTypeUndo, nil, drawer))
+	require.Empty(t, lobby.currentDrawing)
+
+	require.NoError(t, lobby.HandleEvent(EventTypeRedo, nil, drawer))
+	require.Len(t, lobby.currentDrawing, 2)
+	require.NoError(t, lobby.HandleEvent(EventTypeRedo, nil, drawer))
+	require.Len(t, lobby.currentDrawing, 3)
+	require.NoError(t, lobby.HandleEvent(EventTypeRedo, nil, drawer))
+	require.Len(t, lobby.currentDrawing, 3)
+
+	// Drawing something new, invalidates the redo stack.
+	require.NoError(t, lobby.HandleEvent(EventTypeUndo, nil, drawer))
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line(3), drawer))
+	require.NoError(t, lobby.HandleEvent(EventTypeRedo, nil, drawer))
+	require.Len(t, lobby.currentDrawing, 3)
+	require.Equal(t, uint32(3), lobby.currentDrawing[2].(*LineEvent).Data.StrokeID)
+}
+
+func Test_shapeEvents(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  10,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		ScoreCalculation: ChillScoring,
+		words:            []string{"abc", "def", "ghi"},
+	}
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	drawer := lobby.JoinPlayer("drawer")
+	drawer.Connected = true
+	lobby.OwnerID = drawer.ID
+	startLobbyAndChooseWord(t, lobby, drawer)
+
+	line := []byte(`{"type":"line","data":{"x":1,"y":1,"x2":2,"y2":2,"width":8}}`)
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line, drawer))
+	require.NoError(t, lobby.HandleEvent(EventTypeRectangle,
+		[]byte(`{"type":"ellipse","data":{"x":10,"y":10,"x2":200,"y2":100,"width":255,"filled":true}}`), drawer))
+	require.NoError(t, lobby.HandleEvent(EventTypeStraightLine,
+		[]byte(`{"type":"straight-line","data":{"x":10,"y":10,"x2":200,"y2":100,"width":8,"filled":true}}`), drawer))
+	// Lines directly following a shape belong to a new stroke.
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line, drawer))
+	require.Len(t, lobby.currentDrawing, 4)
+
+	rectangle := lobby.currentDrawing[1].(*ShapeEvent)
+	require.Equal(t, EventTypeRectangle, rectangle.Type)
+	require.Equal(t, uint8(MaxBrushSize), rectangle.Data.Width)
+	require.True(t, rectangle.Data.Filled)
+	require.False(t, lobby.currentDrawing[2].(*ShapeEvent).Data.Filled)
+
+	// Each shape is undone on its own.
+	for _, expectedLength := range []int{3, 2, 1, 0} {
+		require.NoError(t, lobby.HandleEvent(EventTypeUndo, nil, drawer))
+		require.Len(t, lobby.currentDrawing, expectedLength)
+	}
+
+	// Shapes out of bounds are rejected, just like lines.
+	require.NoError(t, lobby.HandleEvent(EventTypeEllipse,
+		[]byte(`{"type":"ellipse","data":{"x":10,"y":10,"x2":32000,"y2":100,"width":8}}`), drawer))
+	require.Empty(t, lobby.currentDrawing)
+}
+
+func Test_whiteboardMode(t *testing.T) {
+	t.Parallel()
+
+	owner, lobby, err := CreateLobby("", "owner", "english", &EditableLobbySettings{
+		DrawingTime:        120,
+		Rounds:             4,
+		MaxPlayers:         4,
+		CustomWordsPerTurn: 3,
+		ClientsPerIPLimit:  2,
+		WordsPerTurn:       3,
+	}, nil, ChillScoring, WhiteboardMode)
+	require.NoError(t, err)
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+	owner.Connected = true
+
+	guest := lobby.JoinPlayer("guest")
+	guest.Connected = true
+	spectator := lobby.JoinPlayer("spectator")
+	spectator.Connected = true
+
+	require.Equal(t, Ongoing, lobby.State)
+	require.Empty(t, lobby.CurrentWord)
+	require.Equal(t, Drawing, owner.State)
+	require.Equal(t, Drawing, guest.State)
+
+	line := func(strokeID int) []byte {
+		return fmt.Appendf(nil, `{"type":"line","data":{"x":1,"y":1,"x2":2,"y2":2,"width":8,"strokeId":%d}}`, strokeID)
+	}
+
+	// Spectating applies instantly, as there are no turns.
+	require.NoError(t, lobby.HandleEvent(EventTypeToggleSpectate, nil, spectator))
+	require.Equal(t, Spectating, spectator.State)
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line(1), spectator))
+	require.Empty(t, lobby.currentDrawing)
+
+	// Everyone draws at the same time, with interleaving strokes.
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line(1), owner))
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line(1), guest))
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line(1), owner))
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line(2), guest))
+	require.Len(t, lobby.currentDrawing, 4)
+
+	// Undo only affects the strokes of the player undoing.
+	require.NoError(t, lobby.HandleEvent(EventTypeUndo, nil, owner))
+	require.Len(t, lobby.currentDrawing, 2)
+	require.Equal(t, uint32(2), lobby.currentDrawing[1].(*LineEvent).Data.StrokeID)
+	require.NoError(t, lobby.HandleEvent(EventTypeUndo, nil, owner))
+	require.Len(t, lobby.currentDrawing, 2)
+
+	require.NoError(t, lobby.HandleEvent(EventTypeUndo, nil, guest))
+	require.Len(t, lobby.currentDrawing, 1)
+	require.NoError(t, lobby.HandleEvent(EventTypeRedo, nil, guest))
+	require.NoError(t, lobby.HandleEvent(EventTypeRedo, nil, owner))
+	require.Len(t, lobby.currentDrawing, 4)
+
+	// Only the owner may clear the shared board.
+	require.NoError(t, lobby.HandleEvent(EventTypeClearDrawingBoard, nil, guest))
+	require.Len(t, lobby.currentDrawing, 4)
+	require.NoError(t, lobby.HandleEvent(EventTypeClearDrawingBoard, nil, owner))
+	require.Empty(t, lobby.currentDrawing)
+
+	// Kicking doesn't advance the lobby, since there are no turns.
+	kickPlayer(lobby, guest, 1)
+	require.Equal(t, Ongoing, lobby.State)
+	require.Equal(t, Drawing, owner.State)
+	require.Len(t, lobby.players, 2)
+}
+
+func Test_telephoneMode(t *testing.T) {
+	t.Parallel()
+
+	alice, lobby, err := CreateLobby("", "alice", "english", &EditableLobbySettings{
+		DrawingTime:        120,
+		Rounds:             4,
+		MaxPlayers:         4,
+		CustomWordsPerTurn: 3,
+		ClientsPerIPLimit:  3,
+		WordsPerTurn:       3,
+	}, nil, ChillScoring, TelephoneMode)
+	require.NoError(t, err)
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+	alice.Connected = true
+
+	bob := lobby.JoinPlayer("bob")
+	bob.Connected = true
+	carol := lobby.JoinPlayer("carol")
+	carol.Connected = true
+
+	submit := func(player *Player, text string) {
+		t.Helper()
+		require.NoError(t, lobby.HandleEvent(EventTypeTelephoneSubmit,
+			fmt.Appendf(nil, `{"type":"telephone-submit","data":{"text":%q}}`, text), player))
+	}
+	line := []byte(`{"type":"line","data":{"x":1,"y":1,"x2":2,"y2":2,"width":8}}`)
+
+	require.NoError(t, lobby.HandleEvent(EventTypeStart, nil, alice))
+	require.Equal(t, Ongoing, lobby.State)
+	require.Equal(t, TelephoneTaskPrompt, lobby.telephone.task())
+	require.False(t, lobby.canDraw(alice))
+
+	submit(alice, "cat")
+	submit(alice, "dog")
+	submit(bob, "house")
+	require.Equal(t, 0, lobby.telephone.step)
+	submit(carol, "tree")
+
+	// Everyone draws the prompt of the next player on a private board.
+	require.Equal(t, TelephoneTaskDraw, lobby.telephone.task())
+	require.Equal(t, "house", lobby.telephoneStep(0).Text)
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line, alice))
+	require.NoError(t, lobby.HandleEvent(EventTypeLine, line, carol))
+	require.Empty(t, lobby.currentDrawing)
+	submit(alice, "")
+	require.False(t, lobby.canDraw(alice))
+
+	// Timing out submits the drawings as they are.
+	lobby.advanceTelephone()
+	require.Equal(t, TelephoneTaskDescribe, lobby.telephone.task())
+	// Bob describes the drawing of carol, which is based on the prompt of
+	// alice. Bob didn't draw anything for alice to describe.
+	require.Len(t, lobby.telephoneStep(1).Drawing, 1)
+	require.Empty(t, lobby.telephoneStep(0).Drawing)
+
+	submit(alice, "a tree")
+	submit(bob, "my house")
+	submit(carol, "a "+strings.Repeat("very ", 30)+"long description")
+	require.Equal(t, GameOver, lobby.State)
+
+	chains := lobby.telephone.chains
+	require.Len(t, chains, 3)
+	for _, chain := range chains {
+		require.Len(t, chain.Entries, 3)
+	}
+	require.Equal(t, "cat", chains[0].Entries[0].Text)
+	require.Equal(t, carol.ID, chains[0].Entries[1].AuthorID)
+	require.Equal(t, bob.ID, chains[0].Entries[2].AuthorID)
+	require.Equal(t, "my house", chains[0].Entries[2].Text)
+	require.Len(t, []rune(chains[1].Entries[2].Text), MaxTelephoneTextLength)
+}
+
+func Test_coDrawers(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  10,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		GameplaySettings: GameplaySettings{
+			DrawersPerTurn:   2,
+			SplitDrawerScore: true,
+		},
+		ScoreCalculation: ChillScoring,
+		words: []string{
+			"abc", "def", "ghi", "jkl", "mno", "pqr",
+			"stu", "vwx", "yza", "bcd", "efg", "hij",
+			"klm", "nop", "qrs", "tuv", "wxy", "zab",
+		},
+	}
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	var players []*Player
+	for _, name := range []string{"a", "b", "c", "d"} {
+		player := lobby.JoinPlayer(name)
+		player.Connected = true
+		players = append(players, player)
+	}
+	lobby.OwnerID = players[0].ID
+
+	require.NoError(t, lobby.HandleEvent(EventTypeStart, nil, players[0]))
+	require.Equal(t, players[0], lobby.Drawer())
+	require.Equal(t, []*Player{players[0], players[1]}, lobby.Drawers())
+	require.True(t, players[1].CoDrawing)
+
+	// Only the drawer may choose the word.
+	require.NoError(t, lobby.HandleEvent(EventTypeChooseWord, []byte(`{"data": 0}`), players[1]))
+	require.Empty(t, lobby.CurrentWord)
+	require.NoError(t, lobby.HandleEvent(EventTypeChooseWord, []byte(`{"data": 0}`), players[0]))
+	require.NotEmpty(t, lobby.CurrentWord)
+	require.True(t, lobby.canDraw(players[0]))
+	require.True(t, lobby.canDraw(players[1]))
+	require.Equal(t, lobby.wordHintsShown, lobby.GetAvailableWordHints(players[1]))
+
+	// A disconnected drawer doesn't end the turn, as long as the other
+	// drawer is still around.
+	disconnectTime := time.Now().Add(-2 * disconnectGrace)
+	players[0].Connected = false
+	players[0].disconnectTime = &disconnectTime
+	require.False(t, lobby.shouldEndEarlyDueToDisconnectedDrawer())
+	players[1].Connected = false
+	players[1].disconnectTime = &disconnectTime
+	require.True(t, lobby.shouldEndEarlyDueToDisconnectedDrawer())
+	players[0].Connected = true
+	players[1].Connected = true
+
+	// The drawer score is split between both drawers.
+	players[2].LastScore = 100
+	players[3].LastScore = 100
+	advanceLobby(lobby)
+	require.Equal(t, 50, players[0].Score)
+	require.Equal(t, 50, players[1].Score)
+
+	// The turn order isn't affected by the co-drawer and wraps around.
+	require.Equal(t, players[1], lobby.Drawer())
+	require.True(t, players[2].CoDrawing)
+	advanceLobby(lobby)
+	advanceLobby(lobby)
+	require.Equal(t, players[3], lobby.Drawer())
+	require.True(t, players[0].CoDrawing)
+
+	// Kicking the co-drawer doesn't end the turn.
+	kickPlayer(lobby, players[0], 0)
+	require.Equal(t, players[3], lobby.Drawer())
+	require.Equal(t, 1, lobby.Round)
+
+	// Without enough guessers left, there's only a single drawer.
+	advanceLobby(lobby)
+	require.Len(t, lobby.Drawers(), 1)
+}
+
+func Test_coDrawersDrawerKicked(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  10,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		GameplaySettings: GameplaySettings{DrawersPerTurn: 2},
+		ScoreCalculation: ChillScoring,
+		words:            []string{"abc", "def", "ghi", "jkl", "mno", "pqr"},
+	}
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	var players []*Player
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		player := lobby.JoinPlayer(name)
+		player.Connected = true
+		players = append(players, player)
+	}
+	lobby.OwnerID = players[0].ID
+
+	require.NoError(t, lobby.HandleEvent(EventTypeStart, nil, players[0]))
+	require.NoError(t, lobby.HandleEvent(EventTypeChooseWord, []byte(`{"data": 0}`), players[0]))
+	require.True(t, players[1].CoDrawing)
+	players[2].LastScore = 100
+	players[2].Score = 100
+
+	// The co-drawer isn't promoted, instead the whole turn ends without
+	// anyone earning points.
+	kickPlayer(lobby, players[0], 0)
+	require.Zero(t, players[1].LastScore)
+	require.Zero(t, players[1].Score)
+	require.Zero(t, players[2].Score)
+
+	// The next turn starts as usual.
+	require.Equal(t, players[1], lobby.Drawer())
+	require.False(t, players[1].CoDrawing)
+	require.True(t, players[2].CoDrawing)
+	require.Empty(t, lobby.CurrentWord)
+	require.Equal(t, 1, lobby.Round)
+}
+
+func Test_guessTimeCap(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  120,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		GameplaySettings: GameplaySettings{GuessTimeCap: 30},
+		ScoreCalculation: ChillScoring,
+		words:            []string{"abcdefg", "abcdefg", "abcdefg"},
+		lowercaser:       WordlistData["english"].Lowercaser(),
+	}
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	drawer := lobby.JoinPlayer("drawer")
+	drawer.Connected = true
+	lobby.OwnerID = drawer.ID
+	var guessers []*Player
+	for _, name := range []string{"a", "b", "c"} {
+		guesser := lobby.JoinPlayer(name)
+		guesser.Connected = true
+		guessers = append(guessers, guesser)
+	}
+
+	startLobbyAndChooseWord(t, lobby, drawer)
+	require.Equal(t, int64(40000), lobby.hintInterval)
+
+	handleMessage("abcdefg", guessers[0], lobby)
+	require.Equal(t, Standby, guessers[0].State)
+	timeLeft := lobby.roundEndTime - getTimeAsMillis()
+	require.LessOrEqual(t, timeLeft, int64(30000))
+	require.Greater(t, timeLeft, int64(29000))
+	// Both hints are now spread over the remaining 30 seconds.
+	require.Equal(t, int64(10000), lobby.hintInterval)
+
+	// Further correct guesses don't extend the turn again.
+	lobby.roundEndTime = getTimeAsMillis() + 5000
+	handleMessage("abcdefg", guessers[1], lobby)
+	require.LessOrEqual(t, lobby.roundEndTime-getTimeAsMillis(), int64(5000))
+	require.Equal(t, 1, lobby.Round)
+}
+
+func Test_guessedPercentageToEnd(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  120,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		GameplaySettings: GameplaySettings{GuessedPercentageToEnd: 50},
+		ScoreCalculation: ChillScoring,
+		words:            []string{"abc", "abc", "abc", "abc", "abc", "abc"},
+		lowercaser:       WordlistData["english"].Lowercaser(),
+	}
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	drawer := lobby.JoinPlayer("drawer")
+	drawer.Connected = true
+	lobby.OwnerID = drawer.ID
+	var guessers []*Player
+	for _, name := range []string{"a", "b", "c", "d"} {
+		guesser := lobby.JoinPlayer(name)
+		guesser.Connected = true
+		guessers = append(guessers, guesser)
+	}
+
+	startLobbyAndChooseWord(t, lobby, drawer)
+
+	handleMessage("abc", guessers[0], lobby)
+	require.Equal(t, drawer, lobby.Drawer())
+
+	// Disconnected guessers aren't taken into account.
+	guessers[3].Connected = false
+	handleMessage("abc", guessers[1], lobby)
+	require.Equal(t, guessers[0], lobby.Drawer())
+}
+
+func Test_revealHint(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  120,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		ScoreCalculation: ChillScoring,
+		words:            []string{"pac-man", "pac-man", "pac-man"},
+	}
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	drawer := lobby.JoinPlayer("drawer")
+	drawer.Connected = true
+	lobby.OwnerID = drawer.ID
+	guesser := lobby.JoinPlayer("guesser")
+	guesser.Connected = true
+
+	startLobbyAndChooseWord(t, lobby, drawer)
+	require.Equal(t, 2, lobby.hintsLeft)
+
+	// Guessers can't reveal hints.
+	require.NoError(t, lobby.HandleEvent(EventTypeRevealHint, []byte(`{"data": 0}`), guesser))
+	require.Equal(t, 2, lobby.hintsLeft)
+
+	// The dash is always visible and out of bounds indexes are ignored.
+	require.NoError(t, lobby.HandleEvent(EventTypeRevealHint, []byte(`{"data": 3}`), drawer))
+	require.NoError(t, lobby.HandleEvent(EventTypeRevealHint, []byte(`{"data": 7}`), drawer))
+	require.Equal(t, 2, lobby.hintsLeft)
+
+	require.NoError(t, lobby.HandleEvent(EventTypeRevealHint, []byte(`{"data": 4}`), drawer))
+	require.Equal(t, 1, lobby.hintsLeft)
+	require.Equal(t, 'm', lobby.wordHints[4].Character)
+	require.True(t, lobby.wordHintsShown[4].Revealed)
+	require.Equal(t, 'm', lobby.GetAvailableWordHints(guesser)[4].Character)
+
+	// Revealing the same character twice doesn't use up another hint.
+	require.NoError(t, lobby.HandleEvent(EventTypeRevealHint, []byte(`{"data": 4}`), drawer))
+	require.Equal(t, 1, lobby.hintsLeft)
+
+	require.NoError(t, lobby.HandleEvent(EventTypeRevealHint, []byte(`{"data": 0}`), drawer))
+	require.NoError(t, lobby.HandleEvent(EventTypeRevealHint, []byte(`{"data": 1}`), drawer))
+	require.Equal(t, 0, lobby.hintsLeft)
+	require.Zero(t, lobby.wordHints[1].Character)
+
+	// Each manual hint costs the drawer the hint bonus.
+	guesser.LastScore = 200
+	require.Equal(t, 200-2*30, lobby.calculateDrawerScore())
+}
+
+func Test_rerollWords(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  120,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		GameplaySettings: GameplaySettings{WordRerollsPerTurn: 1},
+		ScoreCalculation: ChillScoring,
+		words:            []string{"abc", "def", "ghi", "jkl", "mno", "pqr", "stu", "vwx", "yza"},
+	}
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	var yourTurnEvents []*YourTurn
+	lobby.WriteObject = func(_ *Player, object any) error {
+		if event, ok := object.(*Event); ok && event.Type == EventTypeYourTurn {
+			yourTurnEvents = append(yourTurnEvents, event.Data.(*YourTurn))
+		}
+		return nil
+	}
+
+	drawer := lobby.JoinPlayer("drawer")
+	drawer.Connected = true
+	lobby.OwnerID = drawer.ID
+	guesser := lobby.JoinPlayer("guesser")
+	guesser.Connected = true
+
+	require.NoError(t, lobby.HandleEvent(EventTypeStart, nil, drawer))
+	require.Len(t, yourTurnEvents, 1)
+	require.Equal(t, 1, yourTurnEvents[0].RerollsLeft)
+	firstChoice := lobby.wordChoice
+	wordChoiceEndTime := lobby.wordChoiceEndTime
+
+	// Only the drawer can reroll.
+	require.NoError(t, lobby.HandleEvent(EventTypeRerollWords, nil, guesser))
+	require.Len(t, yourTurnEvents, 1)
+
+	require.NoError(t, lobby.HandleEvent(EventTypeRerollWords, nil, drawer))
+	require.Len(t, yourTurnEvents, 2)
+	require.Equal(t, 0, yourTurnEvents[1].RerollsLeft)
+	require.NotEqual(t, firstChoice, lobby.wordChoice)
+	require.Equal(t, lobby.wordChoice, yourTurnEvents[1].Words)
+	require.Less(t, lobby.preSelectedWord, len(lobby.wordChoice))
+	require.Equal(t, wordChoiceEndTime, lobby.wordChoiceEndTime)
+
+	// No rerolls left.
+	require.NoError(t, lobby.HandleEvent(EventTypeRerollWords, nil, drawer))
+	require.Len(t, yourTurnEvents, 2)
+
+	// Rerolling isn't possible after choosing a word.
+	lobby.wordRerollsLeft = 1
+	require.NoError(t, lobby.HandleEvent(EventTypeChooseWord, []byte(`{"data": 0}`), drawer))
+	require.NoError(t, lobby.HandleEvent(EventTypeRerollWords, nil, drawer))
+	require.Len(t, yourTurnEvents, 2)
+
+	// The rerolls are reset for every turn.
+	advanceLobby(lobby)
+	require.Equal(t, 1, yourTurnEvents[len(yourTurnEvents)-1].RerollsLeft)
+}
+
+func Test_skipVote(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  120,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		ScoreCalculation: ChillScoring,
+		words:            []string{"abc", "abc", "abc", "abc", "abc", "abc", "abc", "abc", "abc"},
+		lowercaser:       WordlistData["english"].Lowercaser(),
+	}
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	drawer := lobby.JoinPlayer("drawer")
+	drawer.Connected = true
+	lobby.OwnerID = drawer.ID
+	var guessers []*Player
+	for _, name := range []string{"a", "b", "c", "d"} {
+		guesser := lobby.JoinPlayer(name)
+		guesser.Connected = true
+		guessers = append(guessers, guesser)
+	}
+
+	startLobbyAndChooseWord(t, lobby, drawer)
+	guessers[3].State = Spectating
+	require.Equal(t, 2, calculateVotesNeededToSkip(lobby))
+
+	// Neither the drawer nor spectators can vote.
+	require.NoError(t, lobby.HandleEvent(EventTypeSkipVote, nil, drawer))
+	require.NoError(t, lobby.HandleEvent(EventTypeSkipVote, nil, guessers[3]))
+	require.Equal(t, drawer, lobby.Drawer())
+
+	handleMessage("abc", guessers[0], lobby)
+	require.NoError(t, lobby.HandleEvent(EventTypeSkipVote, nil, guessers[1]))
+	// Voting twice doesn't count.
+	require.NoError(t, lobby.HandleEvent(EventTypeSkipVote, nil, guessers[1]))
+	require.Equal(t, drawer, lobby.Drawer())
+
+	require.NoError(t, lobby.HandleEvent(EventTypeSkipVote, nil, guessers[2]))
+	require.Equal(t, guessers[0], lobby.Drawer())
+	require.Zero(t, drawer.Score)
+	require.Zero(t, drawer.LastScore)
+	// Guessers keep the points they earned before the skip.
+	require.Positive(t, guessers[0].Score)
+
+	// Votes are reset for the next turn.
+	for _, guesser := range guessers {
+		require.False(t, guesser.votedForSkip)
+	}
+
+	// Out of two guessers, both have to vote.
+	require.NoError(t, lobby.HandleEvent(EventTypeChooseWord, []byte(`{"data": 0}`), guessers[0]))
+	guessers[2].Connected = false
+	require.Equal(t, 2, calculateVotesNeededToSkip(lobby))
+	require.NoError(t, lobby.HandleEvent(EventTypeSkipVote, nil, guessers[1]))
+	require.Equal(t, guessers[0], lobby.Drawer())
+	require.NoError(t, lobby.HandleEvent(EventTypeSkipVote, nil, drawer))
+	require.NotEqual(t, guessers[0], lobby.Drawer())
+
+	// A single guesser can skip on their own.
+	guessers[1].Connected = false
+	drawer.Connected = false
+	guessers[0].State = Guessing
+	require.Equal(t, 1, calculateVotesNeededToSkip(lobby))
+}
+
+func Test_idleDrawer(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  120,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		GameplaySettings: GameplaySettings{DrawerIdleTimeout: 20},
+		ScoreCalculation: ChillScoring,
+		words:            []string{"abc", "def", "ghi"},
+	}
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	drawer := lobby.JoinPlayer("drawer")
+	drawer.Connected = true
+	lobby.OwnerID = drawer.ID
+	guesser := lobby.JoinPlayer("guesser")
+	guesser.Connected = true
+
+	startLobbyAndChooseWord(t, lobby, drawer)
+	require.False(t, lobby.shouldEndEarlyDueToIdleDrawer())
+
+	lobby.wordChosenTime = time.Now().Add(-21 * time.Second)
+	require.True(t, lobby.shouldEndEarlyDueToIdleDrawer())
+
+	line := &LineEvent{Type: EventTypeLine}
+	line.Data.Width = MaxBrushSize
+	lobby.handleLineEvent(line, drawer)
+	require.False(t, lobby.shouldEndEarlyDueToIdleDrawer())
+
+	lobby.DrawerIdleTimeout = 0
+	lobby.wordChosenTime = time.Now().Add(-time.Hour)
+	require.False(t, lobby.shouldEndEarlyDueToIdleDrawer())
+}
+
+func Test_idlePlayersSpectate(t *testing.T) {
+	t.Parallel()
+
+	lobby := &Lobby{
+		EditableLobbySettings: EditableLobbySettings{
+			DrawingTime:  120,
+			Rounds:       10,
+			WordsPerTurn: 3,
+		},
+		GameplaySettings: GameplaySettings{IdleTurnsToSpectate: 2},
+		ScoreCalculation: ChillScoring,
+		words: []string{
+			"abc", "def", "ghi", "jkl", "mno", "pqr",
+			"stu", "vwx", "yza", "bcd", "efg", "hij",
+		},
+		lowercaser: WordlistData["english"].Lowercaser(),
+	}
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	drawer := lobby.JoinPlayer("drawer")
+	drawer.Connected = true
+	lobby.OwnerID = drawer.ID
+	active := lobby.JoinPlayer("active")
+	active.Connected = true
+	idle := lobby.JoinPlayer("idle")
+	idle.Connected = true
+
+	startLobbyAndChooseWord(t, lobby, drawer)
+	require.NoError(t, lobby.HandleEvent(EventTypeMessage, []byte(`{"data": "hello"}`), active))
+	// Keep-alive events don't count as activity.
+	require.NoError(t, lobby.HandleEvent(EventTypeKeepAlive, nil, idle))
+
+	advanceLobby(lobby)
+	require.Equal(t, 1, idle.activity.idleTurns)
+	require.Zero(t, active.activity.idleTurns)
+	require.Equal(t, Guessing, idle.State)
+	require.Equal(t, active, lobby.Drawer())
+
+	require.NoError(t, lobby.HandleEvent(EventTypeChooseWord, []byte(`{"data": 0}`), active))
+	require.NoError(t, lobby.HandleEvent(EventTypeMessage, []byte(`{"data": "hello"}`), drawer))
+
+	// The idle player would've been next, but is moved to spectating instead.
+	advanceLobby(lobby)
+	require.Equal(t, Spectating, idle.State)
+	require.Equal(t, drawer, lobby.Drawer())
+	require.Equal(t, 2, lobby.Round)
+}
+
+func Test_minReadyPlayers(t *testing.T) {
+	t.Parallel()
+
+	lobby := NewLobby("", "english", &EditableLobbySettings{
+		DrawingTime:  120,
+		Rounds:       4,
+		MaxPlayers:   4,
+		WordsPerTurn: 3,
+	}, nil, ChillScoring, ClassicMode)
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+	lobby.MinReadyPlayers = 2
+	require.Len(t, lobby.GetPlayers(), 0)
+
+	first := lobby.JoinPlayer("first")
+	first.Connected = true
+	second := lobby.JoinPlayer("second")
+	second.Connected = true
+	third := lobby.JoinPlayer("third")
+	third.Connected = true
+
+	require.NoError(t, lobby.HandleEvent(EventTypeToggleReadiness, nil, first))
+	require.Equal(t, Unstarted, lobby.State)
+
+	// The third player doesn't have to be ready.
+	require.NoError(t, lobby.HandleEvent(EventTypeToggleReadiness, nil, second))
+	require.Equal(t, Ongoing, lobby.State)
+
+	// Once the game is over, it restarts as soon as enough players are ready.
+	lobby.State = GameOver
+	for _, player := range lobby.GetPlayers() {
+		player.State = Standby
+	}
+	require.NoError(t, lobby.HandleEvent(EventTypeToggleReadiness, nil, third))
+	require.Equal(t, GameOver, lobby.State)
+	require.NoError(t, lobby.HandleEvent(EventTypeToggleReadiness, nil, first))
+	require.Equal(t, Ongoing, lobby.State)
+}
+
+func Test_startCountdown(t *testing.T) {
+	t.Parallel()
+
+	lobby := NewLobby("", "english", &EditableLobbySettings{
+		DrawingTime:  120,
+		Rounds:       4,
+		MaxPlayers:   4,
+		WordsPerTurn: 3,
+	}, nil, ChillScoring, ClassicMode)
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+	lobby.MinReadyPlayers = 2
+	lobby.StartCountdown = 1
+
+	first := lobby.JoinPlayer("first")
+	first.Connected = true
+	second := lobby.JoinPlayer("second")
+	second.Connected = true
+
+	// Once the start condition holds, the countdown starts.
+	require.NoError(t, lobby.HandleEvent(EventTypeToggleReadiness, nil, first))
+	require.NoError(t, lobby.HandleEvent(EventTypeToggleReadiness, nil, second))
+	require.Equal(t, Unstarted, lobby.State)
+	require.NotNil(t, lobby.startCountdown)
+	require.Positive(t, generateReadyData(lobby, first).StartCountdown)
+
+	// The countdown is cancelled, as soon as the condition stops holding.
+	require.NoError(t, lobby.HandleEvent(EventTypeToggleReadiness, nil, second))
+	require.Nil(t, lobby.startCountdown)
+	require.Zero(t, generateReadyData(lobby, first).StartCountdown)
+
+	require.NoError(t, lobby.HandleEvent(EventTypeToggleReadiness, nil, second))
+	require.Eventually(t, func() bool {
+		var state State
+		lobby.Synchronized(func() {
+			state = lobby.State
+		})
+		return state == Ongoing
+	}, 3*time.Second, 50*time.Millisecond)
+
+	lobby.Synchronized(func() {
+		require.Nil(t, lobby.startCountdown)
+	})
+}
+
+func Test_scheduledStart(t *testing.T) {
+	t.Parallel()
+
+	createScheduledLobby := func(minReadyPlayers int) (*Lobby, *Player) {
+		lobby := NewLobby("", "english", &EditableLobbySettings{
+			DrawingTime:  120,
+			Rounds:       4,
+			MaxPlayers:   4,
+			WordsPerTurn: 3,
+		}, nil, ChillScoring, ClassicMode)
+		lobby.WriteObject = noOpWriteObject
+		lobby.WritePreparedMessage = noOpWritePreparedMessage
+		lobby.MinReadyPlayers = minReadyPlayers
+
+		player := lobby.JoinPlayer("player")
+		player.Connected = true
+
+		lobby.ScheduleStart(time.Now().Add(200 * time.Millisecond))
+		return lobby, player
+	}
+	lobbyState := func(lobby *Lobby) State {
+		var state State
+		lobby.Synchronized(func() {
+			state = lobby.State
+		})
+		return state
+	}
+
+	started, player := createScheduledLobby(1)
+	require.Positive(t, generateReadyData(started, player).ScheduledStart)
+	// Nobody has to be ready, being connected is enough.
+	require.Eventually(t, func() bool {
+		return lobbyState(started) == Ongoing
+	}, 3*time.Second, 50*time.Millisecond)
+
+	// Not enough players are connected, so the game doesn't start.
+	notStarted, player := createScheduledLobby(2)
+	time.Sleep(400 * time.Millisecond)
+	require.Equal(t, Unstarted, lobbyState(notStarted))
+	notStarted.Synchronized(func() {
+		require.Zero(t, generateReadyData(notStarted, player).ScheduledStart)
+	})
+
+	// Removed lobbies don't start anymore.
+	removed, _ := createScheduledLobby(1)
+	removed.StopTimers()
+	time.Sleep(400 * time.Millisecond)
+	require.Equal(t, Unstarted, lobbyState(removed))
+}
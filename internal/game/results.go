@@ -0,0 +1,127 @@
+package game
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+//
+// This file contains the logic for recording the detailed results of a
+// game, so they can be exported once the game is over. Results are only
+// recorded for the classic game mode.
+//
+
+// GameResults is the detailed outcome of a finished game.
+type GameResults struct {
+	LobbyID   string    `json:"lobbyId"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	// Players contains the final standings, ordered by rank.
+	Players []*GameResult `json:"players"`
+	Turns   []*TurnResult `json:"turns"`
+}
+
+// TurnResult describes a single turn in which a word was drawn.
+type TurnResult struct {
+	Round   int           `json:"round"`
+	Word    string        `json:"word"`
+	Drawers []*TurnDrawer `json:"drawers"`
+	Guesses []*TurnGuess  `json:"guesses"`
+}
+
+// TurnDrawer is a player that drew during a turn.
+type TurnDrawer struct {
+	PlayerID   uuid.UUID `json:"playerId"`
+	PlayerName string    `json:"playerName"`
+	Points     int       `json:"points"`
+}
+
+// TurnGuess is a correct guess made during a turn.
+type TurnGuess struct {
+	PlayerID   uuid.UUID `json:"playerId"`
+	PlayerName string    `json:"playerName"`
+	// GuessTime is the time in milliseconds between choosing the word and
+	// the guess.
+	GuessTime int64 `json:"guessTime"`
+	Points    int   `json:"points"`
+}
+
+// beginGameResults starts recording the results of a new game.
+func (lobby *Lobby) beginGameResults() {
+	lobby.pendingResults = &GameResults{
+		LobbyID:   lobby.LobbyID,
+		StartTime: time.Now(),
+		Turns:     []*TurnResult{},
+	}
+	lobby.pendingTurn = nil
+}
+
+// beginTurnResult starts recording a turn, once the word has been chosen.
+func (lobby *Lobby) beginTurnResult() {
+	if lobby.pendingResults == nil {
+		return
+	}
+
+	lobby.pendingTurn = &TurnResult{
+		Round:   lobby.Round,
+		Word:    lobby.CurrentWord,
+		Drawers: []*TurnDrawer{},
+		Guesses: []*TurnGuess{},
+	}
+}
+
+// recordGuess adds a correct guess to the current turn. The points have to
+// be assigned to the guesser beforehand.
+func (lobby *Lobby) recordGuess(guesser *Player) {
+	if lobby.pendingTurn == nil {
+		return
+	}
+
+	lobby.pendingTurn.Guesses = append(lobby.pendingTurn.Guesses, &TurnGuess{
+		PlayerID:   guesser.ID,
+		PlayerName: guesser.Name,
+		GuessTime:  time.Since(lobby.wordChosenTime).Milliseconds(),
+		Points:     guesser.LastScore,
+	})
+}
+
+// finishTurnResult adds the current turn to the results. The points have to
+// be assigned to the drawers beforehand.
+func (lobby *Lobby) finishTurnResult() {
+	if lobby.pendingTurn == nil {
+		return
+	}
+
+	for _, drawer := range lobby.Drawers() {
+		lobby.pendingTurn.Drawers = append(lobby.pendingTurn.Drawers, &TurnDrawer{
+			PlayerID:   drawer.ID,
+			PlayerName: drawer.Name,
+			Points:     drawer.LastScore,
+		})
+	}
+	lobby.pendingResults.Turns = append(lobby.pendingResults.Turns, lobby.pendingTurn)
+	lobby.pendingTurn = nil
+}
+
+// finishGameResults completes the results using the final standings of the
+// game, making them available via GameResults.
+func (lobby *Lobby) finishGameResults(game *SeriesGame) {
+	if lobby.pendingResults == nil {
+		return
+	}
+
+	lobby.pendingResults.EndTime = game.EndTime
+	lobby.pendingResults.Players = game.Results
+	if lobby.pendingResults.Players == nil {
+		lobby.pendingResults.Players = []*GameResult{}
+	}
+	lobby.results = lobby.pendingResults
+	lobby.pendingResults = nil
+}
+
+// GameResults returns the results of the last finished game or nil if no
+// game has finished yet.
+func (lobby *Lobby) GameResults() *GameResults {
+	return lobby.results
+}
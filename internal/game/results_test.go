@@ -0,0 +1,77 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_gameResults(t *testing.T) {
+	t.Parallel()
+
+	lobby := NewLobby("", "english", &EditableLobbySettings{
+		DrawingTime:  120,
+		Rounds:       1,
+		MaxPlayers:   4,
+		WordsPerTurn: 3,
+	}, nil, ChillScoring, ClassicMode)
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	first := lobby.JoinPlayer("first")
+	first.Connected = true
+	second := lobby.JoinPlayer("second")
+	second.Connected = true
+
+	lobby.Synchronized(func() {
+		lobby.startGame()
+		require.Nil(t, lobby.GameResults())
+
+		// The guesser guesses the first word, ending the turn.
+		drawer := lobby.Drawers()[0]
+		guesser := first
+		if drawer == first {
+			guesser = second
+		}
+		require.NoError(t, lobby.selectWord(0))
+		firstWord := lobby.CurrentWord
+		handleMessage(firstWord, guesser, lobby)
+
+		// Nobody guesses the second word, so the time runs out.
+		require.Equal(t, Ongoing, lobby.State)
+		require.NoError(t, lobby.selectWord(0))
+		secondWord := lobby.CurrentWord
+		advanceLobby(lobby)
+		require.Equal(t, GameOver, lobby.State)
+
+		results := lobby.GameResults()
+		require.NotNil(t, results)
+		require.Equal(t, lobby.LobbyID, results.LobbyID)
+		require.False(t, results.EndTime.Before(results.StartTime))
+
+		require.Len(t, results.Players, 2)
+		require.Equal(t, 1, results.Players[0].Rank)
+		require.Positive(t, results.Players[0].Score)
+
+		require.Len(t, results.Turns, 2)
+		firstTurn := results.Turns[0]
+		require.Equal(t, 1, firstTurn.Round)
+		require.Equal(t, firstWord, firstTurn.Word)
+		require.Len(t, firstTurn.Drawers, 1)
+		require.Equal(t, drawer.ID, firstTurn.Drawers[0].PlayerID)
+		require.Positive(t, firstTurn.Drawers[0].Points)
+		require.Len(t, firstTurn.Guesses, 1)
+		require.Equal(t, guesser.ID, firstTurn.Guesses[0].PlayerID)
+		require.Positive(t, firstTurn.Guesses[0].Points)
+		require.GreaterOrEqual(t, firstTurn.Guesses[0].GuessTime, int64(0))
+
+		secondTurn := results.Turns[1]
+		require.Equal(t, secondWord, secondTurn.Word)
+		require.Equal(t, guesser.ID, secondTurn.Drawers[0].PlayerID)
+		require.Empty(t, secondTurn.Guesses)
+
+		// Starting a new game keeps the results until it's over.
+		lobby.startGame()
+		require.Same(t, results, lobby.GameResults())
+	})
+}
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+//
+// This file contains the logic for playing a series of games in the same
+// lobby. Scores are accumulated across all games of a series and each
+// finished game is kept in a history. Lobbies can also be cloned, in order
+// to play another series with the same settings.
+//
+
+// SeriesGame is a finished game of a series.
+type SeriesGame struct {
+	// Number is the position of the game in the series, starting at 1.
+	Number  int           `json:"number"`
+	EndTime time.Time     `json:"endTime"`
+	Results []*GameResult `json:"results"`
+}
+
+// GameResult is the outcome of a single game for a single player.
+type GameResult struct {
+	PlayerID   uuid.UUID `json:"playerId"`
+	PlayerName string    `json:"playerName"`
+	Score      int       `json:"score"`
+	Rank       int       `json:"rank"`
+}
+
+// LobbyClonedEvent informs the players that the lobby owner has cloned the
+// lobby and invites them to join the new lobby.
+type LobbyClonedEvent struct {
+	LobbyID   string `json:"lobbyId"`
+	LobbyCode string `json:"lobbyCode"`
+	// InviteToken is only set if the new lobby is password protected.
+	InviteToken string `json:"inviteToken,omitempty"`
+}
+
+// recordSeriesGame adds the game that just ended to the series history and
+// adds the scores of the game to the series scores of the players.
+func (lobby *Lobby) recordSeriesGame() *SeriesGame {
+	game := &SeriesGame{
+		Number:  len(lobby.series) + 1,
+		EndTime: time.Now(),
+	}
+	for _, player := range lobby.players {
+		// Spectators that didn't earn any points haven't taken part.
+		if player.Score == 0 && player.State == Spectating {
+			continue
+		}
+
+		player.SeriesScore += player.Score
+		game.Results = append(game.Results, &GameResult{
+			PlayerID:   player.ID,
+			PlayerName: player.Name,
+			Score:      player.Score,
+			Rank:       player.Rank,
+		})
+	}
+	slices.SortStableFunc(game.Results, func(a, b *GameResult) int {
+		return a.Rank - b.Rank
+	})
+
+	lobby.series = append(lobby.series, game)
+	return game
+}
+
+// SeriesHistory returns all finished games of the lobby, oldest first.
+func (lobby *Lobby) SeriesHistory() []*SeriesGame {
+	return lobby.series
+}
+
+// Clone creates a new lobby without any players, but with the same settings
+// and custom words as the lobby. The password is kept as well, but invite
+// tokens aren't. The series isn't carried over.
+func (lobby *Lobby) Clone() *Lobby {
+	settings := lobby.EditableLobbySettings
+	if lobby.DrawingTimeNew != 0 {
+		settings.DrawingTime = lobby.DrawingTimeNew
+	}
+
+	clone := NewLobby("", lobby.Wordpack, &settings, slices.Clone(lobby.CustomWords),
+		lobby.ScoreCalculation, lobby.GameMode)
+	clone.CanvasSettings = lobby.CanvasSettings
+	clone.GameplaySettings = lobby.GameplaySettings
+	clone.Palette = slices.Clone(lobby.Palette)
+	clone.access.passwordSalt = lobby.access.passwordSalt
+	clone.access.passwordHash = lobby.access.passwordHash
+
+	clone.WriteObject = lobby.WriteObject
+	clone.WritePreparedMessage = lobby.WritePreparedMessage
+	clone.DrawingBatchInterval = lobby.DrawingBatchInterval
+
+	return clone
+}
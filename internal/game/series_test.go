@@ -0,0 +1,101 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_seriesScores(t *testing.T) {
+	t.Parallel()
+
+	lobby := NewLobby("", "english", &EditableLobbySettings{
+		DrawingTime:  120,
+		Rounds:       1,
+		MaxPlayers:   4,
+		WordsPerTurn: 3,
+	}, nil, ChillScoring, ClassicMode)
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+
+	first := lobby.JoinPlayer("first")
+	first.Connected = true
+	second := lobby.JoinPlayer("second")
+	second.Connected = true
+	spectator := lobby.JoinPlayer("spectator")
+	spectator.Connected = true
+	spectator.State = Spectating
+
+	first.Score, first.Rank = 100, 1
+	second.Score, second.Rank = 50, 2
+	lobby.recordSeriesGame()
+
+	// Starting a new game resets the scores, but not the series scores.
+	lobby.startGame()
+	require.Zero(t, first.Score)
+	require.Equal(t, 100, first.SeriesScore)
+	require.Equal(t, 50, second.SeriesScore)
+
+	first.Score, first.Rank = 10, 2
+	second.Score, second.Rank = 80, 1
+	lobby.recordSeriesGame()
+
+	require.Equal(t, 110, first.SeriesScore)
+	require.Equal(t, 130, second.SeriesScore)
+	require.Zero(t, spectator.SeriesScore)
+
+	history := lobby.SeriesHistory()
+	require.Len(t, history, 2)
+	require.Equal(t, 1, history[0].Number)
+	require.Equal(t, 2, history[1].Number)
+	// Results are ordered by rank and don't contain idle spectators.
+	require.Len(t, history[1].Results, 2)
+	require.Equal(t, second.ID, history[1].Results[0].PlayerID)
+	require.Equal(t, 80, history[1].Results[0].Score)
+	require.Equal(t, first.ID, history[1].Results[1].PlayerID)
+
+	require.Equal(t, history, generateReadyData(lobby, first).Series)
+}
+
+func Test_lobbyClone(t *testing.T) {
+	t.Parallel()
+
+	player, lobby, err := CreateLobby("", "owner", "english", &EditableLobbySettings{
+		DrawingTime:  120,
+		Rounds:       4,
+		MaxPlayers:   4,
+		WordsPerTurn: 3,
+	}, []string{"custom"}, ChillScoring, ClassicMode)
+	require.NoError(t, err)
+	lobby.DrawersPerTurn = 2
+	lobby.MinReadyPlayers = 3
+	lobby.Palette = []string{"#ffffff", "#000000"}
+	lobby.DrawingTimeNew = 90
+	require.NoError(t, lobby.SetPassword("secret"))
+	lobby.CreateInviteToken(time.Hour)
+	player.Score = 100
+	lobby.recordSeriesGame()
+
+	clone := lobby.Clone()
+	require.NotEqual(t, lobby.LobbyID, clone.LobbyID)
+	require.Empty(t, clone.GetPlayers())
+	require.Equal(t, 90, clone.DrawingTime)
+	require.Equal(t, lobby.Rounds, clone.Rounds)
+	require.Equal(t, lobby.Wordpack, clone.Wordpack)
+	require.Equal(t, lobby.CustomWords, clone.CustomWords)
+	require.Equal(t, 2, clone.DrawersPerTurn)
+	require.Equal(t, 3, clone.MinReadyPlayers)
+	require.Equal(t, lobby.Palette, clone.Palette)
+	require.Equal(t, Unstarted, clone.State)
+	require.Empty(t, clone.SeriesHistory())
+
+	// The password is kept, but invite tokens aren't.
+	require.True(t, clone.CheckPassword("secret"))
+	require.False(t, clone.CheckPassword("wrong"))
+	require.Empty(t, clone.access.inviteTokens)
+
+	// The lobbies mustn't share any state.
+	clone.CustomWords[0] = "changed"
+	require.Equal(t, "custom", lobby.CustomWords[0])
+}
@@ -19,6 +19,15 @@ const (
 	EventTypeRequestDrawing  = "request-drawing"
 	EventTypeChooseWord      = "choose-word"
 	EventTypeUndo            = "undo"
+	EventTypeRedo            = "redo"
+	// EventTypeRevealHint reveals the character at the given index of the
+	// current word. Only the drawer can do this.
+	EventTypeRevealHint = "reveal-hint"
+	// EventTypeRerollWords replaces the words the drawer can choose from.
+	EventTypeRerollWords = "reroll-words"
+	// EventTypeTelephoneSubmit finishes the players task of the current
+	// telephone step.
+	EventTypeTelephoneSubmit = "telephone-submit"
 )
 
 // Events that are outgoing only.
@@ -44,18 +53,59 @@ const (
 	EventTypeLobbySettingsChanged     = "lobby-settings-changed"
 	EventTypeShutdown                 = "shutdown"
 	EventTypeKeepAlive                = "keep-alive"
+	// EventTypeDrawingBatch contains multiple line and fill events, which
+	// should be drawn on top of the current drawing.
+	EventTypeDrawingBatch = "drawing-batch"
+	// EventTypeTelephoneStep is sent privately to each telephone participant
+	// and contains their task for the next step.
+	EventTypeTelephoneStep = "telephone-step"
+	// EventTypeTelephoneSubmitted informs all players about a submission,
+	// but doesn't contain its content.
+	EventTypeTelephoneSubmitted = "telephone-submitted"
+	// EventTypeTelephoneReveal contains all chains of a finished telephone
+	// game.
+	EventTypeTelephoneReveal = "telephone-reveal"
+	// EventTypeUpdateTimeLeft contains the new time left of the turn in
+	// milliseconds, in case the turn has been shortened.
+	EventTypeUpdateTimeLeft = "update-time-left"
+	// EventTypeIdleSpectating informs a player that they have been moved to
+	// spectating due to inactivity.
+	EventTypeIdleSpectating = "idle-spectating"
+	// EventTypeStartCountdown informs all players that the game will start
+	// once the countdown runs out. See StartCountdownEvent.
+	EventTypeStartCountdown = "start-countdown"
+	// EventTypeStartCountdownCancelled informs all players that the start
+	// condition doesn't hold anymore and the game won't start.
+	EventTypeStartCountdownCancelled = "start-countdown-cancelled"
+	// EventTypeLobbyCloned invites all players to the clone of the lobby.
+	// See LobbyClonedEvent.
+	EventTypeLobbyCloned = "lobby-cloned"
 )
 
 // Events that are bidirectional.
 var (
 	EventTypeKickVote          = "kick-vote"
+	EventTypeSkipVote          = "skip-vote"
 	EventTypeNameChange        = "name-change"
 	EventTypeMessage           = "message"
 	EventTypeLine              = "line"
 	EventTypeFill              = "fill"
 	EventTypeClearDrawingBoard = "clear-drawing-board"
+	EventTypeRectangle         = "rectangle"
+	EventTypeEllipse           = "ellipse"
+	EventTypeStraightLine      = "straight-line"
 )
 
+// DrawingTools are the types of draw events that the server accepts and
+// that are therefore allowed to be part of a drawing.
+var DrawingTools = []string{
+	EventTypeLine,
+	EventTypeFill,
+	EventTypeRectangle,
+	EventTypeEllipse,
+	EventTypeStraightLine,
+}
+
 type State string
 
 const (
@@ -114,6 +164,10 @@ type LineEvent struct {
 		// the values are always the same, using an index saves bandwidth.
 		Color uint8 `json:"color"`
 		Width uint8 `json:"width"`
+		// StrokeID groups all lines of a single stroke for undo and redo.
+		// Clients not setting this, cause the server to group lines by the
+		// time passed between them.
+		StrokeID uint32 `json:"strokeId,omitempty"`
 	} `json:"data"`
 }
 
@@ -128,6 +182,28 @@ type FillEvent struct {
 	Type string `json:"type"`
 }
 
+// ShapeEvent is used for rectangles, ellipses and straight lines. Unlike
+// lines, a shape is always drawn at once and is never part of a stroke.
+type ShapeEvent struct {
+	Type string `json:"type"`
+	// Data contains the bounding box of the shape, or the start and end point
+	// in case of a straight line. Just like for lines, the coordinates may be
+	// slightly outside of the canvas.
+	Data struct {
+		X  int16 `json:"x"`
+		Y  int16 `json:"y"`
+		X2 int16 `json:"x2"`
+		Y2 int16 `json:"y2"`
+		// Color is a color index, see LineEvent.
+		Color uint8 `json:"color"`
+		// Width is the width of the outline.
+		Width uint8 `json:"width"`
+		// Filled causes the inside of the shape to be filled with the
+		// outline color. It is ignored for straight lines.
+		Filled bool `json:"filled,omitempty"`
+	} `json:"data"`
+}
+
 // KickVote represents a players vote to kick another players. If the VoteCount
 // is as great or greater than the RequiredVoteCount, the event indicates a
 // successful kick vote. The voting is anonymous, meaning the voting player
@@ -139,6 +215,13 @@ type KickVote struct {
 	RequiredVoteCount int       `json:"requiredVoteCount"`
 }
 
+type SkipVote struct {
+	PlayerName        string    `json:"playerName"`
+	PlayerID          uuid.UUID `json:"playerId"`
+	VoteCount         int       `json:"voteCount"`
+	RequiredVoteCount int       `json:"requiredVoteCount"`
+}
+
 type OwnerChangeEvent struct {
 	PlayerName string    `json:"playerName"`
 	PlayerID   uuid.UUID `json:"playerId"`
@@ -160,6 +243,59 @@ type GameOverEvent struct {
 	RoundEndReason roundEndReason `json:"roundEndReason"`
 }
 
+// TelephoneTask is the task of a telephone participant in a single step.
+type TelephoneTask string
+
+const (
+	// TelephoneTaskPrompt means a prompt has to be written freely.
+	TelephoneTaskPrompt TelephoneTask = "prompt"
+	// TelephoneTaskDraw means the previous text has to be drawn.
+	TelephoneTaskDraw TelephoneTask = "draw"
+	// TelephoneTaskDescribe means the previous drawing has to be described.
+	TelephoneTaskDescribe TelephoneTask = "describe"
+)
+
+// TelephoneStep describes the task of a participant for the current step.
+// Depending on the task, either the text or the drawing of the previous
+// entry in the chain is set.
+type TelephoneStep struct {
+	Step     int           `json:"step"`
+	Steps    int           `json:"steps"`
+	Task     TelephoneTask `json:"task"`
+	TimeLeft int           `json:"timeLeft"`
+	Text     string        `json:"text,omitempty"`
+	Drawing  []any         `json:"drawing,omitempty"`
+	// Submitted is true if the participant has already finished the task.
+	Submitted bool `json:"submitted"`
+}
+
+// TelephoneSubmission is the data of the telephone-submit event. The text is
+// ignored for drawing tasks, as the drawing has already been sent via
+// regular draw events.
+type TelephoneSubmission struct {
+	Text string `json:"text"`
+}
+
+type TelephoneSubmitted struct {
+	PlayerID  uuid.UUID `json:"playerId"`
+	Submitted int       `json:"submitted"`
+	Total     int       `json:"total"`
+}
+
+// TelephoneEntry is a single text or drawing within a chain.
+type TelephoneEntry struct {
+	AuthorID   uuid.UUID `json:"authorId"`
+	AuthorName string    `json:"authorName"`
+	Text       string    `json:"text,omitempty"`
+	Drawing    []any     `json:"drawing,omitempty"`
+}
+
+// TelephoneChain starts with the prompt of a player and is followed by
+// alternating drawings and descriptions of other players.
+type TelephoneChain struct {
+	Entries []*TelephoneEntry `json:"entries"`
+}
+
 type WordChosen struct {
 	TimeLeft int         `json:"timeLeft"`
 	Hints    []*WordHint `json:"hints"`
@@ -169,6 +305,8 @@ type YourTurn struct {
 	TimeLeft        int      `json:"timeLeft"`
 	PreSelectedWord int      `json:"preSelectedWord"`
 	Words           []string `json:"words"`
+	// RerollsLeft is the amount of times the words can still be replaced.
+	RerollsLeft int `json:"rerollsLeft"`
 }
 
 // NextTurn represents the data necessary for displaying the lobby state right
@@ -211,6 +349,26 @@ type ReadyEvent struct {
 	TimeLeft           int         `json:"timeLeft"`
 	DrawingTimeSetting int         `json:"drawingTimeSetting"`
 	AllowDrawing       bool        `json:"allowDrawing"`
+	// Palette is only set if the lobby uses a custom palette.
+	Palette  []string `json:"palette,omitempty"`
+	GameMode GameMode `json:"gameMode"`
+	// TelephoneStep is only set for participants of an ongoing telephone
+	// game.
+	TelephoneStep *TelephoneStep `json:"telephoneStep,omitempty"`
+	// TelephoneChains is only set once a telephone game is over.
+	TelephoneChains []*TelephoneChain `json:"telephoneChains,omitempty"`
+	// MinReadyPlayers is the amount of ready players required for starting
+	// the game. 0 means that all connected players have to be ready.
+	MinReadyPlayers int `json:"minReadyPlayers"`
+	// StartCountdown is the time in milliseconds until the game starts. It
+	// is 0 if there's no countdown running.
+	StartCountdown int `json:"startCountdown"`
+	// ScheduledStart is the time in milliseconds until the scheduled start
+	// of the game. It is 0 if the lobby isn't scheduled or the time has
+	// already passed.
+	ScheduledStart int `json:"scheduledStart"`
+	// Series contains all finished games of the lobby, oldest first.
+	Series []*SeriesGame `json:"series,omitempty"`
 }
 
 type Ring[T any] struct {
@@ -253,14 +411,28 @@ type Player struct {
 	// userSession uniquely identifies the player.
 	userSession uuid.UUID
 	ws          *gws.Conn
+	// binaryEncoding indicates whether the client negotiated the binary
+	// format for drawing events during the websocket upgrade.
+	binaryEncoding bool
 	// disconnectTime is used to kick a player in case the lobby doesn't have
 	// space for new players. The player with the oldest disconnect.Time will
 	// get kicked.
-	disconnectTime   *time.Time
-	votedForKick     map[uuid.UUID]bool
+	disconnectTime *time.Time
+	votedForKick   map[uuid.UUID]bool
+	// votedForSkip is reset at the start of each turn.
+	votedForSkip     bool
 	lastKnownAddress string
 	// messageTimestamps are stored for ratelimiting reasons. See handleMessage.
 	messageTimestamps *Ring[time.Time]
+	// drawRateLimiter and undoRateLimiter prevent drawers from flooding the
+	// other players. See checkDrawEventLimits.
+	drawRateLimiter eventRateLimiter
+	undoRateLimiter eventRateLimiter
+	// lastDrawingFullNotice is used to avoid flooding the player with
+	// messages. See sendDrawingFullNotice.
+	lastDrawingFullNotice time.Time
+	drawHistory           drawHistory
+	activity              playerActivity
 
 	// Name is the players displayed name
 	Name  string      `json:"name"`
@@ -270,11 +442,18 @@ type Player struct {
 	// state. While this will allow people to skip being the drawer, it will
 	// also cause them to lose points for that round.
 	SpectateToggleRequested bool `json:"spectateToggleRequested"`
+	// CoDrawing is set for players that assist the drawer in cooperative
+	// turns. They are in the drawing state as well, but don't choose the
+	// word and don't count as the drawer for the turn order.
+	CoDrawing bool `json:"coDrawing,omitempty"`
 	// Rank is the current ranking of the player in his Lobby
 	// Score is the points that the player got in the current Lobby.
 	Score     int `json:"score"`
 	LastScore int `json:"lastScore"`
 	Rank      int `json:"rank"`
+	// SeriesScore is the sum of the scores of all finished games of the
+	// series played in the current Lobby.
+	SeriesScore int `json:"seriesScore"`
 	// Connected defines whether the players websocket connection is currently
 	// established. This has previously been in state but has been moved out
 	// in order to avoid losing the state on refreshing the page.
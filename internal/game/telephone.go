@@ -0,0 +1,335 @@
+package game
+
+import (
+	"strings"
+	"time"
+)
+
+//
+// This file contains the logic for lobbies in TelephoneMode. Every participant
+// starts a chain by writing a prompt. In each following step, every
+// participant works on a different chain, alternating between drawing the
+// latest text and describing the latest drawing. All chains are revealed once
+// every participant has worked on every chain.
+//
+
+const (
+	// telephoneMinParticipants is the minimum amount of participants, since
+	// a single player couldn't pass anything on.
+	telephoneMinParticipants = 2
+	// telephoneTextTime is the time in seconds for writing prompts and
+	// descriptions. Drawing steps use the lobbies drawing time instead.
+	telephoneTextTime = 60
+	// MaxTelephoneTextLength is the maximum amount of characters of prompts
+	// and descriptions. Longer texts are cut off.
+	MaxTelephoneTextLength = 100
+)
+
+type telephoneGame struct {
+	participants []*telephoneParticipant
+	// chains contains one chain per participant, in the same order.
+	chains []*TelephoneChain
+	// step is the index of the current step. Each chain has exactly one
+	// entry per finished step.
+	step        int
+	stepEndTime time.Time
+}
+
+type telephoneParticipant struct {
+	player *Player
+	// drawingBoard is private to the participant. Draw events on it aren't
+	// forwarded to anyone and it is reset on each step.
+	drawingBoard drawingBoard
+	submitted    bool
+}
+
+func (telephone *telephoneGame) task() TelephoneTask {
+	if telephone.step == 0 {
+		return TelephoneTaskPrompt
+	}
+	if telephone.step%2 == 1 {
+		return TelephoneTaskDraw
+	}
+	return TelephoneTaskDescribe
+}
+
+// chainIndex returns the index of the chain the participant works on in the
+// current step. Since the offset grows with each step, no participant works
+// on the same chain twice.
+func (telephone *telephoneGame) chainIndex(participantIndex int) int {
+	return (participantIndex + telephone.step) % len(telephone.participants)
+}
+
+// participant returns the index and state of the given player or -1 and nil,
+// if the player doesn't participate.
+func (telephone *telephoneGame) participant(player *Player) (int, *telephoneParticipant) {
+	for index, participant := range telephone.participants {
+		if participant.player.ID == player.ID {
+			return index, participant
+		}
+	}
+
+	return -1, nil
+}
+
+func (telephone *telephoneGame) submissionCount() int {
+	var count int
+	for _, participant := range telephone.participants {
+		if participant.submitted {
+			count++
+		}
+	}
+	return count
+}
+
+// everyoneSubmitted decides whether the step can end early. Disconnected
+// participants aren't waited for, but we never skip a step without any
+// submission.
+func (telephone *telephoneGame) everyoneSubmitted() bool {
+	var anySubmitted bool
+	for _, participant := range telephone.participants {
+		if participant.submitted {
+			anySubmitted = true
+		} else if participant.player.Connected {
+			return false
+		}
+	}
+
+	return anySubmitted
+}
+
+// currentTelephoneParticipant returns the participant of the ongoing
+// telephone game or nil, if there's none.
+func (lobby *Lobby) currentTelephoneParticipant(player *Player) (int, *telephoneParticipant) {
+	if lobby.State != Ongoing || lobby.telephone == nil {
+		return -1, nil
+	}
+
+	return lobby.telephone.participant(player)
+}
+
+func (lobby *Lobby) canDrawTelephone(player *Player) bool {
+	_, participant := lobby.currentTelephoneParticipant(player)
+	return participant != nil && !participant.submitted &&
+		lobby.telephone.task() == TelephoneTaskDraw
+}
+
+// activeDrawingBoard returns the board that the player draws on. The second
+// return value indicates whether the board is private, meaning that changes
+// mustn't be forwarded to other players.
+func (lobby *Lobby) activeDrawingBoard(player *Player) (*drawingBoard, bool) {
+	if lobby.GameMode == TelephoneMode {
+		if _, participant := lobby.currentTelephoneParticipant(player); participant != nil {
+			return &participant.drawingBoard, true
+		}
+	}
+
+	return &lobby.drawingBoard, false
+}
+
+// startTelephone starts a new telephone game with all connected players that
+// aren't spectating. If there aren't enough players, nothing happens.
+func (lobby *Lobby) startTelephone() {
+	var participants []*telephoneParticipant
+	for _, player := range lobby.players {
+		if player.Connected && player.State != Spectating {
+			participants = append(participants, &telephoneParticipant{player: player})
+		}
+	}
+
+	if len(participants) < telephoneMinParticipants {
+		lobby.Broadcast(&Event{Type: EventTypeUpdatePlayers, Data: lobby.players})
+		return
+	}
+
+	chains := make([]*TelephoneChain, len(participants))
+	for index, participant := range participants {
+		participant.player.State = Guessing
+		chains[index] = &TelephoneChain{}
+	}
+
+	lobby.telephone = &telephoneGame{
+		participants: participants,
+		chains:       chains,
+	}
+	lobby.State = Ongoing
+	lobby.ClearDrawing()
+
+	lobby.Broadcast(&Event{Type: EventTypeUpdatePlayers, Data: lobby.players})
+	lobby.startTelephoneStep()
+}
+
+func (lobby *Lobby) startTelephoneStep() {
+	telephone := lobby.telephone
+
+	stepDuration := telephoneTextTime
+	if telephone.task() == TelephoneTaskDraw {
+		stepDuration = lobby.DrawingTime
+	}
+	telephone.stepEndTime = time.Now().Add(time.Duration(stepDuration) * time.Second)
+
+	for index, participant := range telephone.participants {
+		participant.submitted = false
+		participant.drawingBoard.reset()
+		participant.player.drawHistory = drawHistory{}
+
+		if participant.player.Connected {
+			lobby.WriteObject(participant.player, &Event{
+				Type: EventTypeTelephoneStep,
+				Data: lobby.telephoneStep(index),
+			})
+		}
+	}
+
+	lobby.timeLeftTicker = time.NewTicker(1 * time.Second)
+	go startTurnTimeTicker(lobby, lobby.timeLeftTicker)
+}
+
+// telephoneStep returns the task of the participant at the given index,
+// including the latest entry of the chain they are working on.
+func (lobby *Lobby) telephoneStep(participantIndex int) *TelephoneStep {
+	telephone := lobby.telephone
+	step := &TelephoneStep{
+		Step:      telephone.step,
+		Steps:     len(telephone.participants),
+		Task:      telephone.task(),
+		TimeLeft:  int(time.Until(telephone.stepEndTime).Milliseconds()),
+		Submitted: telephone.participants[participantIndex].submitted,
+	}
+
+	if telephone.step > 0 {
+		chain := telephone.chains[telephone.chainIndex(participantIndex)]
+		previous := chain.Entries[telephone.step-1]
+		step.Text = previous.Text
+		step.Drawing = previous.Drawing
+	}
+
+	return step
+}
+
+func (lobby *Lobby) handleTelephoneSubmitEvent(player *Player, submission *TelephoneSubmission) {
+	index, participant := lobby.currentTelephoneParticipant(player)
+	if participant == nil || participant.submitted {
+		return
+	}
+
+	lobby.submitTelephoneEntry(index, submission.Text)
+	lobby.Broadcast(&Event{
+		Type: EventTypeTelephoneSubmitted,
+		Data: &TelephoneSubmitted{
+			PlayerID:  player.ID,
+			Submitted: lobby.telephone.submissionCount(),
+			Total:     len(lobby.telephone.participants),
+		},
+	})
+
+	if lobby.telephone.everyoneSubmitted() {
+		lobby.advanceTelephone()
+	}
+}
+
+// submitTelephoneEntry adds the entry of the participant to the chain they
+// are working on. For drawing tasks, the private drawing is used, otherwise
+// the given text.
+func (lobby *Lobby) submitTelephoneEntry(participantIndex int, text string) {
+	telephone := lobby.telephone
+	participant := telephone.participants[participantIndex]
+	participant.submitted = true
+
+	entry := &TelephoneEntry{
+		AuthorID:   participant.player.ID,
+		AuthorName: participant.player.Name,
+	}
+	if telephone.task() == TelephoneTaskDraw {
+		entry.Drawing = participant.drawingBoard.currentDrawing
+	} else {
+		entry.Text = strings.TrimSpace(text)
+		if runes := []rune(entry.Text); len(runes) > MaxTelephoneTextLength {
+			entry.Text = string(runes[:MaxTelephoneTextLength])
+		}
+	}
+
+	chain := telephone.chains[telephone.chainIndex(participantIndex)]
+	chain.Entries = append(chain.Entries, entry)
+}
+
+// telephoneTickLogic is the equivalent of tickLogic for TelephoneMode. The
+// caller has to hold the lock.
+func (lobby *Lobby) telephoneTickLogic() bool {
+	if time.Now().Before(lobby.telephone.stepEndTime) && !lobby.telephone.everyoneSubmitted() {
+		return true
+	}
+
+	lobby.advanceTelephone()
+	return false
+}
+
+// advanceTelephone ends the current step, even if not all participants have
+// submitted yet. Drawings are submitted as is, texts are left empty.
+func (lobby *Lobby) advanceTelephone() {
+	// Causes the ticker routine to stop, see advanceLobbyPredefineDrawer.
+	lobby.timeLeftTicker = nil
+
+	telephone := lobby.telephone
+	for index, participant := range telephone.participants {
+		if !participant.submitted {
+			lobby.submitTelephoneEntry(index, "")
+		}
+	}
+
+	telephone.step++
+	if telephone.step < len(telephone.participants) {
+		lobby.startTelephoneStep()
+		return
+	}
+
+	lobby.State = GameOver
+	for _, player := range lobby.players {
+		// Toggling spectate mode can't be applied during a telephone game, as
+		// it would break the chains.
+		if player.SpectateToggleRequested {
+			player.SpectateToggleRequested = false
+			if player.State == Spectating {
+				player.State = Standby
+			} else {
+				player.State = Spectating
+			}
+		}
+	}
+
+	lobby.Broadcast(&Event{Type: EventTypeTelephoneReveal, Data: telephone.chains})
+	for _, player := range lobby.players {
+		readyData := generateReadyData(lobby, player)
+		// The chains are already part of the reveal event.
+		readyData.TelephoneChains = nil
+		lobby.WriteObject(player, Event{
+			Type: EventTypeGameOver,
+			Data: &GameOverEvent{ReadyEvent: readyData},
+		})
+	}
+}
+
+// addTelephoneReadyData adds the state of the telephone game to the ready
+// event, so that players can continue after reconnecting.
+func (lobby *Lobby) addTelephoneReadyData(ready *ReadyEvent, player *Player) {
+	telephone := lobby.telephone
+	if telephone == nil {
+		return
+	}
+
+	if lobby.State == GameOver {
+		ready.TelephoneChains = telephone.chains
+		return
+	}
+
+	index, participant := lobby.currentTelephoneParticipant(player)
+	if participant == nil {
+		ready.TimeLeft = int(time.Until(telephone.stepEndTime).Milliseconds())
+		return
+	}
+
+	ready.TelephoneStep = lobby.telephoneStep(index)
+	ready.TimeLeft = ready.TelephoneStep.TimeLeft
+	ready.CurrentDrawing = participant.drawingBoard.currentDrawing
+	ready.AllowDrawing = lobby.canDraw(player)
+}
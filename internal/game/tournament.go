@@ -0,0 +1,480 @@
+package game
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	mathrand "math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+//
+// This file contains the logic for tournaments. A tournament distributes its
+// participants across several qualifier lobbies with identical settings. The
+// best players of each qualifier are seeded into a final lobby. Tournament
+// lobbies are password protected, as participants enter them via the
+// tournament instead.
+//
+// Lobbies always have to be locked before their tournament, as the
+// tournament is notified about finished games while the lobby is locked.
+//
+
+// TournamentStage describes the progress of a tournament.
+type TournamentStage string
+
+const (
+	// TournamentRegistration is the initial stage, in which participants
+	// can register.
+	TournamentRegistration TournamentStage = "registration"
+	// TournamentQualifying means that the qualifier lobbies are playing.
+	TournamentQualifying TournamentStage = "qualifying"
+	// TournamentFinal means that the finalists have been seeded into the
+	// final lobby.
+	TournamentFinal TournamentStage = "final"
+	// TournamentFinished means that the first game of the final is over.
+	TournamentFinished TournamentStage = "finished"
+)
+
+const (
+	// maxTournamentDuration is the time after which tournaments that haven't
+	// finished are considered abandoned.
+	maxTournamentDuration = 24 * time.Hour
+	// maxTournamentRegistrationDuration is the time after which tournaments
+	// that haven't been started are considered abandoned.
+	maxTournamentRegistrationDuration = 6 * time.Hour
+)
+
+var (
+	ErrTournamentNotInRegistration   = errors.New("the tournament isn't accepting registrations anymore")
+	ErrTournamentFull                = errors.New("the tournament has reached its maximum amount of participants")
+	ErrTournamentNotQualifying       = errors.New("the tournament isn't in the qualifying stage")
+	ErrTournamentNotStarted          = errors.New("the tournament hasn't started yet")
+	ErrTournamentParticipantNotFound = errors.New("the tournament participant doesn't exist")
+	ErrTournamentLobbyFull           = errors.New("the tournament lobby is already full")
+
+	// errTournamentLobbyChanged indicates that the participant has been
+	// moved to another lobby while trying to enter their lobby.
+	errTournamentLobbyChanged = errors.New("the tournament lobby has changed")
+)
+
+// Tournament is a competition across several lobbies.
+type Tournament struct {
+	ID string
+	// FinalistsPerLobby is the amount of best ranked players of each
+	// qualifier lobby that are seeded into the final lobby.
+	FinalistsPerLobby int
+	// MaxParticipants limits the amount of participants that can register.
+	MaxParticipants int
+
+	// organizerToken authorizes managing the tournament.
+	organizerToken string
+	createdAt      time.Time
+
+	mutex sync.Mutex
+	stage TournamentStage
+	// starting is set while the tournament lobbies are being created. It
+	// closes the registration, without changing the public stage yet.
+	starting bool
+	// template is never played in, but all tournament lobbies are cloned
+	// from it.
+	template     *Lobby
+	participants []*TournamentParticipant
+	qualifiers   []*Lobby
+	final        *Lobby
+	// results contains the first finished game of each tournament lobby.
+	results map[*Lobby]*SeriesGame
+}
+
+// TournamentParticipant is a registered player of a tournament.
+type TournamentParticipant struct {
+	// ID is secret, as it allows entering the lobby of the participant.
+	ID       uuid.UUID
+	Name     string
+	Finalist bool
+
+	// lobby is the lobby the participant is currently assigned to.
+	lobby *Lobby
+	// playerID is the ID of the player in the assigned lobby. It is
+	// uuid.Nil if the participant hasn't entered the lobby yet.
+	playerID uuid.UUID
+}
+
+// TournamentOverview is the public state of a tournament.
+type TournamentOverview struct {
+	ID                string                          `json:"id"`
+	Stage             TournamentStage                 `json:"stage"`
+	FinalistsPerLobby int                             `json:"finalistsPerLobby"`
+	Participants      []*TournamentParticipantSummary `json:"participants"`
+	Qualifiers        []*TournamentLobbySummary       `json:"qualifiers"`
+	// Final is only set once the tournament has been started.
+	Final *TournamentLobbySummary `json:"final,omitempty"`
+}
+
+// TournamentParticipantSummary is the public part of a TournamentParticipant.
+type TournamentParticipantSummary struct {
+	Name     string `json:"name"`
+	Finalist bool   `json:"finalist"`
+	// LobbyID is only set once the tournament has been started.
+	LobbyID string `json:"lobbyId,omitempty"`
+}
+
+// TournamentLobbySummary describes a single lobby of a tournament.
+type TournamentLobbySummary struct {
+	LobbyID string `json:"lobbyId"`
+	// Results are only set once the first game of the lobby is over.
+	Results *SeriesGame `json:"results,omitempty"`
+}
+
+// NewTournament creates a tournament in the registration stage. All
+// tournament lobbies will be clones of the template lobby.
+func NewTournament(template *Lobby, finalistsPerLobby, maxParticipants int) *Tournament {
+	return &Tournament{
+		ID:                uuid.Must(uuid.NewV4()).String(),
+		FinalistsPerLobby: finalistsPerLobby,
+		MaxParticipants:   maxParticipants,
+		organizerToken:    rand.Text(),
+		createdAt:         time.Now(),
+		stage:             TournamentRegistration,
+		template:          template,
+		results:           make(map[*Lobby]*SeriesGame),
+	}
+}
+
+// OrganizerToken returns the token required for managing the tournament.
+func (tournament *Tournament) OrganizerToken() string {
+	return tournament.organizerToken
+}
+
+// CheckOrganizerToken checks whether the given token authorizes managing
+// the tournament.
+func (tournament *Tournament) CheckOrganizerToken(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(tournament.organizerToken)) == 1
+}
+
+// Register adds a new participant to the tournament.
+func (tournament *Tournament) Register(name string) (*TournamentParticipant, error) {
+	tournament.mutex.Lock()
+	defer tournament.mutex.Unlock()
+
+	if tournament.stage != TournamentRegistration || tournament.starting {
+		return nil, ErrTournamentNotInRegistration
+	}
+	if len(tournament.participants) >= tournament.MaxParticipants {
+		return nil, ErrTournamentFull
+	}
+
+	participant := &TournamentParticipant{
+		ID:   uuid.Must(uuid.NewV4()),
+		Name: SanitizeName(name),
+	}
+	tournament.participants = append(tournament.participants, participant)
+	return participant, nil
+}
+
+// Start distributes the participants evenly across as few qualifier lobbies
+// as possible and creates the final lobby. The returned lobbies have to be
+// made available by the caller.
+func (tournament *Tournament) Start() ([]*Lobby, error) {
+	lobbyCount, err := tournament.closeRegistration()
+	if err != nil {
+		return nil, err
+	}
+
+	// Hashing the lobby passwords is expensive, so the lobbies are created
+	// without holding the lock. The closed registration prevents any changes
+	// in the meantime.
+	lobbies, err := tournament.newTournamentLobbies(lobbyCount + 1)
+
+	tournament.mutex.Lock()
+	defer tournament.mutex.Unlock()
+
+	tournament.starting = false
+	if err != nil {
+		return nil, err
+	}
+
+	tournament.qualifiers = lobbies[:lobbyCount]
+	tournament.final = lobbies[lobbyCount]
+
+	participants := slices.Clone(tournament.participants)
+	mathrand.Shuffle(len(participants), func(a, b int) {
+		participants[a], participants[b] = participants[b], participants[a]
+	})
+	for index, participant := range participants {
+		participant.lobby = tournament.qualifiers[index%lobbyCount]
+	}
+
+	tournament.stage = TournamentQualifying
+	return lobbies, nil
+}
+
+// closeRegistration checks whether the tournament can be started and
+// returns the amount of qualifier lobbies required.
+func (tournament *Tournament) closeRegistration() (int, error) {
+	tournament.mutex.Lock()
+	defer tournament.mutex.Unlock()
+
+	if tournament.stage != TournamentRegistration || tournament.starting {
+		return 0, ErrTournamentNotInRegistration
+	}
+	if len(tournament.participants) < 2 {
+		return 0, errors.New("a tournament requires at least two participants")
+	}
+
+	maxPlayers := tournament.template.MaxPlayers
+	lobbyCount := (len(tournament.participants) + maxPlayers - 1) / maxPlayers
+	if lobbyCount*tournament.FinalistsPerLobby > maxPlayers {
+		return 0, fmt.Errorf("%d finalists from %d lobbies don't fit into the final lobby",
+			tournament.FinalistsPerLobby, lobbyCount)
+	}
+
+	tournament.starting = true
+	return lobbyCount, nil
+}
+
+func (tournament *Tournament) newTournamentLobbies(count int) ([]*Lobby, error) {
+	lobbies := make([]*Lobby, 0, count)
+	for range count {
+		lobby := tournament.template.Clone()
+		lobby.Public = false
+		lobby.Tournament = tournament
+		// Nobody knows the password, so only participants can join.
+		if err := lobby.SetPassword(rand.Text()); err != nil {
+			return nil, err
+		}
+		lobbies = append(lobbies, lobby)
+	}
+
+	return lobbies, nil
+}
+
+// Advance seeds the finalists into the final lobby, without waiting for
+// the remaining qualifier lobbies to finish their game.
+func (tournament *Tournament) Advance() error {
+	tournament.mutex.Lock()
+	defer tournament.mutex.Unlock()
+
+	if tournament.stage != TournamentQualifying {
+		return ErrTournamentNotQualifying
+	}
+
+	tournament.seedFinal()
+	return nil
+}
+
+// seedFinal moves the best ranked participants of each finished qualifier
+// into the final lobby.
+func (tournament *Tournament) seedFinal() {
+	for _, lobby := range tournament.qualifiers {
+		game := tournament.results[lobby]
+		if game == nil {
+			continue
+		}
+
+		var seeded int
+		// Results are ordered by rank.
+		for _, result := range game.Results {
+			if seeded == tournament.FinalistsPerLobby {
+				break
+			}
+
+			participant := tournament.participantByPlayer(lobby, result.PlayerID)
+			if participant == nil {
+				continue
+			}
+
+			participant.Finalist = true
+			participant.lobby = tournament.final
+			participant.playerID = uuid.Nil
+			seeded++
+		}
+	}
+
+	tournament.stage = TournamentFinal
+}
+
+func (tournament *Tournament) participantByPlayer(lobby *Lobby, playerID uuid.UUID) *TournamentParticipant {
+	for _, participant := range tournament.participants {
+		if participant.lobby == lobby && participant.playerID == playerID {
+			return participant
+		}
+	}
+	return nil
+}
+
+func (tournament *Tournament) participant(id uuid.UUID) *TournamentParticipant {
+	for _, participant := range tournament.participants {
+		if participant.ID == id {
+			return participant
+		}
+	}
+	return nil
+}
+
+// handleGameOver records the results of the first game of each tournament
+// lobby. Once all qualifiers are over, the finalists are seeded. The lobby
+// has to be locked.
+func (tournament *Tournament) handleGameOver(lobby *Lobby, game *SeriesGame) {
+	tournament.mutex.Lock()
+	defer tournament.mutex.Unlock()
+
+	// Games played after the first one don't count.
+	if _, recorded := tournament.results[lobby]; recorded {
+		return
+	}
+
+	if lobby == tournament.final {
+		if tournament.stage == TournamentFinal {
+			tournament.results[lobby] = game
+			tournament.stage = TournamentFinished
+		}
+		return
+	}
+
+	if tournament.stage != TournamentQualifying {
+		return
+	}
+
+	tournament.results[lobby] = game
+	for _, qualifier := range tournament.qualifiers {
+		if tournament.results[qualifier] == nil {
+			return
+		}
+	}
+	tournament.seedFinal()
+}
+
+// Enter returns the lobby the participant is currently assigned to and the
+// participants player in it. If the participant hasn't entered the lobby
+// before, a new player is added to the lobby, given that there's a free slot.
+// Since other players can join tournament lobbies as well, the slots of
+// participants aren't reserved.
+func (tournament *Tournament) Enter(participantID uuid.UUID) (*Lobby, *Player, error) {
+	for {
+		tournament.mutex.Lock()
+		participant := tournament.participant(participantID)
+		var lobby *Lobby
+		if participant != nil {
+			lobby = participant.lobby
+		}
+		tournament.mutex.Unlock()
+
+		if participant == nil {
+			return nil, nil, ErrTournamentParticipantNotFound
+		}
+		if lobby == nil {
+			return nil, nil, ErrTournamentNotStarted
+		}
+
+		player, err := tournament.enterLobby(participant, lobby)
+		if errors.Is(err, errTournamentLobbyChanged) {
+			// The participant has been seeded into the final in the meantime.
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return lobby, player, nil
+	}
+}
+
+func (tournament *Tournament) enterLobby(participant *TournamentParticipant, lobby *Lobby) (*Player, error) {
+	lobby.mutex.Lock()
+	defer lobby.mutex.Unlock()
+	tournament.mutex.Lock()
+	defer tournament.mutex.Unlock()
+
+	if participant.lobby != lobby {
+		return nil, errTournamentLobbyChanged
+	}
+
+	player := lobby.GetPlayerByID(participant.playerID)
+	if player == nil {
+		if !lobby.HasFreePlayerSlot() {
+			return nil, ErrTournamentLobbyFull
+		}
+
+		player = lobby.JoinPlayer(participant.Name)
+		participant.playerID = player.ID
+	}
+	return player, nil
+}
+
+// Lobbies returns all lobbies created by the tournament.
+func (tournament *Tournament) Lobbies() []*Lobby {
+	tournament.mutex.Lock()
+	defer tournament.mutex.Unlock()
+
+	if tournament.final == nil {
+		return nil
+	}
+	return append(slices.Clone(tournament.qualifiers), tournament.final)
+}
+
+// IsFinished indicates whether the final is over.
+func (tournament *Tournament) IsFinished() bool {
+	tournament.mutex.Lock()
+	defer tournament.mutex.Unlock()
+
+	return tournament.stage == TournamentFinished
+}
+
+// IsAbandoned indicates whether the tournament has been running for too long
+// without finishing, or has never been started.
+func (tournament *Tournament) IsAbandoned() bool {
+	tournament.mutex.Lock()
+	defer tournament.mutex.Unlock()
+
+	age := time.Since(tournament.createdAt)
+	switch tournament.stage {
+	case TournamentFinished:
+		return false
+	case TournamentRegistration:
+		return !tournament.starting && age > maxTournamentRegistrationDuration
+	default:
+		return age > maxTournamentDuration
+	}
+}
+
+// Overview returns the current public state of the tournament.
+func (tournament *Tournament) Overview() *TournamentOverview {
+	tournament.mutex.Lock()
+	defer tournament.mutex.Unlock()
+
+	overview := &TournamentOverview{
+		ID:                tournament.ID,
+		Stage:             tournament.stage,
+		FinalistsPerLobby: tournament.FinalistsPerLobby,
+		Participants:      make([]*TournamentParticipantSummary, 0, len(tournament.participants)),
+		Qualifiers:        make([]*TournamentLobbySummary, 0, len(tournament.qualifiers)),
+	}
+	for _, participant := range tournament.participants {
+		summary := &TournamentParticipantSummary{
+			Name:     participant.Name,
+			Finalist: participant.Finalist,
+		}
+		if participant.lobby != nil {
+			summary.LobbyID = participant.lobby.LobbyID
+		}
+		overview.Participants = append(overview.Participants, summary)
+	}
+	for _, lobby := range tournament.qualifiers {
+		overview.Qualifiers = append(overview.Qualifiers, &TournamentLobbySummary{
+			LobbyID: lobby.LobbyID,
+			Results: tournament.results[lobby],
+		})
+	}
+	if tournament.final != nil {
+		overview.Final = &TournamentLobbySummary{
+			LobbyID: tournament.final.LobbyID,
+			Results: tournament.results[tournament.final],
+		}
+	}
+
+	return overview
+}
@@ -0,0 +1,259 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func createTournamentTestTemplate() *Lobby {
+	lobby := NewLobby("", "english", &EditableLobbySettings{
+		DrawingTime:  120,
+		Rounds:       1,
+		MaxPlayers:   4,
+		WordsPerTurn: 3,
+	}, nil, ChillScoring, ClassicMode)
+	lobby.WriteObject = noOpWriteObject
+	lobby.WritePreparedMessage = noOpWritePreparedMessage
+	return lobby
+}
+
+// finishTournamentTestGame ranks all players of the lobby in join order and
+// ends the game.
+func finishTournamentTestGame(lobby *Lobby) {
+	lobby.Synchronized(func() {
+		for index, player := range lobby.players {
+			player.Rank = index + 1
+			player.Score = 100 - index
+		}
+		lobby.Tournament.handleGameOver(lobby, lobby.recordSeriesGame())
+	})
+}
+
+func Test_tournamentStart(t *testing.T) {
+	t.Parallel()
+
+	tournament := NewTournament(createTournamentTestTemplate(), 1, 100)
+	_, err := tournament.Start()
+	require.Error(t, err, "a single participant isn't enough")
+
+	for index := range 10 {
+		_, err := tournament.Register(fmt.Sprintf("player%d", index))
+		require.NoError(t, err)
+	}
+
+	lobbies, err := tournament.Start()
+	require.NoError(t, err)
+	// Three qualifiers for 10 players with 4 slots each, plus the final.
+	require.Len(t, lobbies, 4)
+	require.Equal(t, lobbies, tournament.Lobbies())
+
+	_, err = tournament.Register("late")
+	require.ErrorIs(t, err, ErrTournamentNotInRegistration)
+
+	playersPerLobby := make(map[string]int)
+	for _, participant := range tournament.Overview().Participants {
+		playersPerLobby[participant.LobbyID]++
+	}
+	require.Len(t, playersPerLobby, 3)
+	for _, playerCount := range playersPerLobby {
+		require.GreaterOrEqual(t, playerCount, 3)
+		require.LessOrEqual(t, playerCount, 4)
+	}
+
+	for _, lobby := range lobbies {
+		require.Same(t, tournament, lobby.Tournament)
+		require.False(t, lobby.Public)
+		require.True(t, lobby.HasPassword())
+		require.Empty(t, lobby.players)
+	}
+}
+
+func Test_tournamentTooManyFinalists(t *testing.T) {
+	t.Parallel()
+
+	tournament := NewTournament(createTournamentTestTemplate(), 3, 100)
+	for index := range 5 {
+		_, err := tournament.Register(fmt.Sprintf("player%d", index))
+		require.NoError(t, err)
+	}
+
+	// Two qualifiers with three finalists each exceed the final lobby.
+	_, err := tournament.Start()
+	require.Error(t, err)
+}
+
+func Test_tournamentMaxParticipants(t *testing.T) {
+	t.Parallel()
+
+	tournament := NewTournament(createTournamentTestTemplate(), 1, 3)
+	for index := range 3 {
+		_, err := tournament.Register(fmt.Sprintf("player%d", index))
+		require.NoError(t, err)
+	}
+
+	_, err := tournament.Register("late")
+	require.ErrorIs(t, err, ErrTournamentFull)
+	require.Len(t, tournament.Overview().Participants, 3)
+}
+
+func Test_tournamentAbandoned(t *testing.T) {
+	t.Parallel()
+
+	tournament := NewTournament(createTournamentTestTemplate(), 1, 100)
+	require.False(t, tournament.IsAbandoned())
+
+	// Tournaments that never start are abandoned before running ones.
+	tournament.createdAt = time.Now().Add(-maxTournamentRegistrationDuration - time.Minute)
+	require.True(t, tournament.IsAbandoned())
+
+	for index := range 2 {
+		_, err := tournament.Register(fmt.Sprintf("player%d", index))
+		require.NoError(t, err)
+	}
+	_, err := tournament.Start()
+	require.NoError(t, err)
+	require.False(t, tournament.IsAbandoned())
+
+	tournament.createdAt = time.Now().Add(-maxTournamentDuration - time.Minute)
+	require.True(t, tournament.IsAbandoned())
+}
+
+func Test_tournamentSeeding(t *testing.T) {
+	t.Parallel()
+
+	tournament := NewTournament(createTournamentTestTemplate(), 2, 100)
+	var participants []*TournamentParticipant
+	for index := range 6 {
+		participant, err := tournament.Register(fmt.Sprintf("player%d", index))
+		require.NoError(t, err)
+		participants = append(participants, participant)
+	}
+
+	_, _, err := tournament.Enter(participants[0].ID)
+	require.ErrorIs(t, err, ErrTournamentNotStarted)
+	_, _, err = tournament.Enter(uuid.Must(uuid.NewV4()))
+	require.ErrorIs(t, err, ErrTournamentParticipantNotFound)
+
+	lobbies, err := tournament.Start()
+	require.NoError(t, err)
+	require.Len(t, lobbies, 3)
+	final := lobbies[2]
+
+	for _, participant := range participants {
+		lobby, player, err := tournament.Enter(participant.ID)
+		require.NoError(t, err)
+		require.NotSame(t, final, lobby)
+		require.Equal(t, participant.Name, player.Name)
+
+		// Entering again returns the same player.
+		_, samePlayer, err := tournament.Enter(participant.ID)
+		require.NoError(t, err)
+		require.Same(t, player, samePlayer)
+	}
+
+	finishTournamentTestGame(lobbies[0])
+	require.Equal(t, TournamentQualifying, tournament.Overview().Stage)
+	// Games after the first don't count.
+	finishTournamentTestGame(lobbies[0])
+
+	finishTournamentTestGame(lobbies[1])
+	overview := tournament.Overview()
+	require.Equal(t, TournamentFinal, overview.Stage)
+	require.Equal(t, final.LobbyID, overview.Final.LobbyID)
+	for _, qualifier := range overview.Qualifiers {
+		require.NotNil(t, qualifier.Results)
+	}
+
+	var finalists int
+	for _, participant := range participants {
+		lobby, _, err := tournament.Enter(participant.ID)
+		require.NoError(t, err)
+		if participant.Finalist {
+			finalists++
+			require.Same(t, final, lobby)
+		} else {
+			require.NotSame(t, final, lobby)
+		}
+	}
+	require.Equal(t, 4, finalists)
+	require.Len(t, final.players, 4)
+
+	require.False(t, tournament.IsFinished())
+	finishTournamentTestGame(final)
+	require.True(t, tournament.IsFinished())
+	require.NotNil(t, tournament.Overview().Final.Results)
+}
+
+func Test_tournamentLobbyFull(t *testing.T) {
+	t.Parallel()
+
+	tournament := NewTournament(createTournamentTestTemplate(), 1, 100)
+	var participants []*TournamentParticipant
+	for index := range 2 {
+		participant, err := tournament.Register(fmt.Sprintf("player%d", index))
+		require.NoError(t, err)
+		participants = append(participants, participant)
+	}
+	_, err := tournament.Start()
+	require.NoError(t, err)
+
+	first, _, err := tournament.Enter(participants[0].ID)
+	require.NoError(t, err)
+
+	// Slots aren't reserved for participants, so other players can take
+	// them before the participant has entered.
+	first.Synchronized(func() {
+		for first.HasFreePlayerSlot() {
+			first.JoinPlayer("stranger")
+		}
+	})
+	_, _, err = tournament.Enter(participants[1].ID)
+	require.ErrorIs(t, err, ErrTournamentLobbyFull)
+
+	// Participants that have already entered can still come back.
+	_, _, err = tournament.Enter(participants[0].ID)
+	require.NoError(t, err)
+}
+
+func Test_tournamentAdvance(t *testing.T) {
+	t.Parallel()
+
+	tournament := NewTournament(createTournamentTestTemplate(), 1, 100)
+	require.ErrorIs(t, tournament.Advance(), ErrTournamentNotQualifying)
+
+	var participants []*TournamentParticipant
+	for index := range 5 {
+		participant, err := tournament.Register(fmt.Sprintf("player%d", index))
+		require.NoError(t, err)
+		participants = append(participants, participant)
+	}
+	lobbies, err := tournament.Start()
+	require.NoError(t, err)
+
+	for _, participant := range participants {
+		_, _, err := tournament.Enter(participant.ID)
+		require.NoError(t, err)
+	}
+
+	// Only the first qualifier has finished, so only its winner is seeded.
+	finishTournamentTestGame(lobbies[0])
+	require.NoError(t, tournament.Advance())
+	require.Equal(t, TournamentFinal, tournament.Overview().Stage)
+	require.ErrorIs(t, tournament.Advance(), ErrTournamentNotQualifying)
+
+	var finalists int
+	for _, participant := range participants {
+		if participant.Finalist {
+			finalists++
+		}
+	}
+	require.Equal(t, 1, finalists)
+
+	// Late qualifier results are ignored.
+	finishTournamentTestGame(lobbies[1])
+	require.Nil(t, tournament.Overview().Qualifiers[1].Results)
+}
@@ -10,6 +10,7 @@ import (
 var (
 	registry         *prometheus.Registry
 	connectedPlayers prometheus.Gauge
+	drawingLimitHits *prometheus.CounterVec
 )
 
 func init() {
@@ -20,7 +21,13 @@ func init() {
 		Help:      "The amount of connected players (active websocket connections)",
 	})
 
-	registry.MustRegister(connectedPlayers)
+	drawingLimitHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "scribblers",
+		Name:      "drawing_limit_hits_total",
+		Help:      "The amount of drawing events dropped due to a limit, such as ratelimiting or bounds",
+	}, []string{"limit"})
+
+	registry.MustRegister(connectedPlayers, drawingLimitHits)
 }
 
 func TrackPlayerConnect() {
@@ -31,6 +38,12 @@ func TrackPlayerDisconnect() {
 	connectedPlayers.Dec()
 }
 
+// TrackDrawingLimitHit counts a dropped drawing event. The limit describes
+// which limit caused the event to be dropped.
+func TrackDrawingLimitHit(limit string) {
+	drawingLimitHits.WithLabelValues(limit).Inc()
+}
+
 func SetupRoute(registerFunc func(http.HandlerFunc)) {
 	registerFunc(promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP)
 }
@@ -0,0 +1,60 @@
+package state
+
+import (
+	"crypto/rand"
+	"log"
+	"strings"
+
+	"github.com/scribble-rs/scribble.rs/internal/game"
+)
+
+// lobbyCodeAlphabet leaves out characters that are easily confused, such as
+// 0 and O or 1, I and L, so that codes can be read out loud or copied by hand.
+const lobbyCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+const (
+	lobbyCodeLength = 6
+	// maxLobbyCodeAttempts limits how often we try finding an unused code.
+	// With roughly 900 million possible codes, running out of attempts is
+	// practically impossible.
+	maxLobbyCodeAttempts = 100
+)
+
+// generateLobbyCode creates a random code. It doesn't check whether the code
+// is already in use.
+func generateLobbyCode() string {
+	randomBytes := make([]byte, lobbyCodeLength)
+	// As of Go 1.24, Read never returns an error.
+	_, _ = rand.Read(randomBytes)
+
+	code := make([]byte, lobbyCodeLength)
+	for index, randomByte := range randomBytes {
+		// The modulo bias is negligible for this use case.
+		code[index] = lobbyCodeAlphabet[int(randomByte)%len(lobbyCodeAlphabet)]
+	}
+	return string(code)
+}
+
+// normalizeLobbyCode allows users to enter codes without caring about case
+// or surrounding whitespace.
+func normalizeLobbyCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// assignLobbyCode assigns an unused code to the lobby, retrying on collisions.
+// The caller has to hold the globalStateMutex.
+func assignLobbyCode(lobby *game.Lobby, generateCode func() string) {
+	for range maxLobbyCodeAttempts {
+		code := generateCode()
+		if _, inUse := lobbyCodes[code]; inUse {
+			continue
+		}
+
+		lobby.LobbyCode = code
+		lobbyCodes[code] = lobby
+		return
+	}
+
+	// The lobby is still reachable via its ID.
+	log.Printf("Couldn't find unused code for lobby %s\n", lobby.LobbyID)
+}
@@ -0,0 +1,80 @@
+package state
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scribble-rs/scribble.rs/internal/game"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_generateLobbyCode(t *testing.T) {
+	t.Parallel()
+
+	for range 100 {
+		code := generateLobbyCode()
+		require.Len(t, code, lobbyCodeLength)
+		for _, character := range code {
+			require.True(t, strings.ContainsRune(lobbyCodeAlphabet, character))
+		}
+	}
+}
+
+func Test_lobbyCodeAlphabetUnambiguous(t *testing.T) {
+	t.Parallel()
+
+	for _, ambiguous := range "01ILO" {
+		require.False(t, strings.ContainsRune(lobbyCodeAlphabet, ambiguous))
+	}
+}
+
+//nolint:paralleltest //this test is very stateful
+func TestLobbyCodes(t *testing.T) {
+	lobbyA := &game.Lobby{LobbyID: "a"}
+	lobbyB := &game.Lobby{LobbyID: "b"}
+	AddLobby(lobbyA)
+	AddLobby(lobbyB)
+	t.Cleanup(func() {
+		RemoveLobby(lobbyA.LobbyID)
+		RemoveLobby(lobbyB.LobbyID)
+	})
+
+	require.NotEmpty(t, lobbyA.LobbyCode)
+	require.NotEqual(t, lobbyA.LobbyCode, lobbyB.LobbyCode)
+	require.Same(t, lobbyA, GetLobby(lobbyA.LobbyCode))
+	require.Same(t, lobbyB, GetLobby(" "+strings.ToLower(lobbyB.LobbyCode)+" "))
+	require.Nil(t, GetLobby(""))
+
+	codeA := lobbyA.LobbyCode
+	RemoveLobby(lobbyA.LobbyID)
+	require.Nil(t, GetLobby(codeA))
+	require.NotContains(t, lobbyCodes, codeA)
+}
+
+//nolint:paralleltest //this test is very stateful
+func Test_assignLobbyCodeCollision(t *testing.T) {
+	codes := []string{"AAAAAA", "AAAAAA", "BBBBBB"}
+	generateCode := func() string {
+		code := codes[0]
+		codes = codes[1:]
+		return code
+	}
+
+	existing := &game.Lobby{LobbyID: "existing"}
+	lobby := &game.Lobby{LobbyID: "new"}
+	assignLobbyCode(existing, generateCode)
+	assignLobbyCode(lobby, generateCode)
+	t.Cleanup(func() {
+		clear(lobbyCodes)
+	})
+
+	require.Equal(t, "AAAAAA", existing.LobbyCode)
+	require.Equal(t, "BBBBBB", lobby.LobbyCode)
+	require.Same(t, lobby, lobbyCodes["BBBBBB"])
+
+	// If no unused code can be found, the lobby is only reachable via its ID.
+	unlucky := &game.Lobby{LobbyID: "unlucky"}
+	assignLobbyCode(unlucky, func() string { return "AAAAAA" })
+	require.Empty(t, unlucky.LobbyCode)
+	require.Same(t, existing, lobbyCodes["AAAAAA"])
+}
@@ -2,6 +2,7 @@ package state
 
 import (
 	"log"
+	"slices"
 	"sync"
 	"time"
 
@@ -12,6 +13,8 @@ import (
 var (
 	globalStateMutex = &sync.RWMutex{}
 	lobbies          []*game.Lobby
+	// lobbyCodes maps the short codes of all lobbies to the lobbies.
+	lobbyCodes = make(map[string]*game.Lobby)
 )
 
 // LaunchCleanupRoutine starts a task to clean up empty lobbies. An empty
@@ -44,31 +47,48 @@ func cleanupRoutineLogic(cfg *config.LobbyCleanup) {
 	initalLobbyCount := len(lobbies)
 	for index := len(lobbies) - 1; index >= 0; index-- {
 		lobby := lobbies[index]
-		if lobby.HasConnectedPlayers() {
+		if lobby.Permanent || lobby.HasConnectedPlayers() {
+			continue
+		}
+		// Participants might join their lobby late or not at all, so we
+		// have to wait until the tournament is over.
+		if lobby.Tournament != nil && !lobby.Tournament.IsFinished() {
 			continue
 		}
 
 		disconnectTime := lobby.LastPlayerDisconnectTime
+		// Scheduled lobbies are kept until their start, even if nobody has
+		// joined. Afterwards, players get the usual time to show up.
+		if !lobby.ScheduledStart.IsZero() &&
+			(disconnectTime == nil || disconnectTime.Before(lobby.ScheduledStart)) {
+			disconnectTime = &lobby.ScheduledStart
+		}
 		if disconnectTime == nil || time.Since(*disconnectTime) >= cfg.PlayerInactivityThreshold {
 			removeLobbyByIndex(index)
 		}
 	}
 
+	// Abandoned tournaments take their lobbies with them.
+	cleanupTournaments()
+	cleanupResults(cfg.ResultsRetention)
+
 	if lobbiesClosed := initalLobbyCount - len(lobbies); lobbiesClosed > 0 {
 		log.Printf("Closing %d lobbies. Remaining lobbies: %d\n", lobbiesClosed, len(lobbies))
 	}
 }
 
 // AddLobby adds a lobby to the instance, making it visible for GetLobby calls.
+// This also assigns an unused LobbyCode to the lobby.
 func AddLobby(lobby *game.Lobby) {
 	globalStateMutex.Lock()
 	defer globalStateMutex.Unlock()
 
+	assignLobbyCode(lobby, generateLobbyCode)
 	lobbies = append(lobbies, lobby)
 }
 
-// GetLobby returns a Lobby that has a matching ID or no Lobby if none could
-// be found.
+// GetLobby returns a Lobby that has a matching ID or LobbyCode or no Lobby
+// if none could be found.
 func GetLobby(id string) *game.Lobby {
 	globalStateMutex.RLock()
 	defer globalStateMutex.RUnlock()
@@ -79,7 +99,7 @@ func GetLobby(id string) *game.Lobby {
 		}
 	}
 
-	return nil
+	return lobbyCodes[normalizeLobbyCode(id)]
 }
 
 // ShutdownLobbiesGracefully shuts down all lobbies and removes them from the
@@ -99,6 +119,9 @@ func ShutdownLobbiesGracefully() {
 
 	// Instead of removing one by one, we nil the array, since that's faster.
 	lobbies = nil
+	clear(lobbyCodes)
+	clear(tournaments)
+	clear(archivedResults)
 }
 
 // GetActiveLobbyCount indicates how many activate lobby there are. This includes
@@ -113,7 +136,7 @@ func GetActiveLobbyCount() int {
 
 // GetPublicLobbies returns all lobbies with their public flag set to true.
 // This implies that the lobbies can be found in the lobby browser ob the
-// homepage.
+// homepage. Permanent lobbies are returned first.
 func GetPublicLobbies() []*game.Lobby {
 	globalStateMutex.RLock()
 	defer globalStateMutex.RUnlock()
@@ -125,6 +148,16 @@ func GetPublicLobbies() []*game.Lobby {
 		}
 	}
 
+	slices.SortStableFunc(publicLobbies, func(a, b *game.Lobby) int {
+		if a.Permanent == b.Permanent {
+			return 0
+		}
+		if a.Permanent {
+			return -1
+		}
+		return 1
+	})
+
 	return publicLobbies
 }
 
@@ -146,6 +179,9 @@ func removeLobby(id string) {
 }
 
 func removeLobbyByIndex(index int) {
+	lobbies[index].StopTimers()
+	archiveResults(lobbies[index])
+	delete(lobbyCodes, lobbies[index].LobbyCode)
 	// We delete the lobby without maintaining order, since the lobby order
 	// is irrelevant. This holds true as long as there's no paging for
 	// requesting lobbies via the API.
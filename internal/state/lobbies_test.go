@@ -2,7 +2,9 @@ package state
 
 import (
 	"testing"
+	"time"
 
+	"github.com/gofrs/uuid/v5"
 	"github.com/scribble-rs/scribble.rs/internal/config"
 	"github.com/scribble-rs/scribble.rs/internal/game"
 	"github.com/stretchr/testify/require"
@@ -21,7 +23,7 @@ func TestAddAndRemove(t *testing.T) {
 			CustomWordsPerTurn: 3,
 			ClientsPerIPLimit:  1,
 			WordsPerTurn:       3,
-		}, nil, game.ChillScoring)
+		}, nil, game.ChillScoring, game.ClassicMode)
 		require.NoError(t, err)
 		lobby.OnPlayerDisconnect(player)
 		return lobby
@@ -48,3 +50,128 @@ func TestAddAndRemove(t *testing.T) {
 	cleanupRoutineLogic(&config.LobbyCleanup{})
 	require.Empty(t, lobbies)
 }
+
+//nolint:paralleltest //this test is very stateful
+func TestPermanentLobbies(t *testing.T) {
+	createLobby := func(permanent bool) *game.Lobby {
+		lobby := game.NewLobby("", "english", &game.EditableLobbySettings{
+			Public:       true,
+			DrawingTime:  100,
+			Rounds:       10,
+			MaxPlayers:   10,
+			WordsPerTurn: 3,
+		}, nil, game.ChillScoring, game.ClassicMode)
+		lobby.Permanent = permanent
+		return lobby
+	}
+	regular := createLobby(false)
+	permanent := createLobby(true)
+
+	AddLobby(regular)
+	AddLobby(permanent)
+
+	// Permanent lobbies are listed before all other lobbies.
+	require.Equal(t, []*game.Lobby{permanent, regular}, GetPublicLobbies())
+
+	// Permanent lobbies survive without players.
+	cleanupRoutineLogic(&config.LobbyCleanup{})
+	require.Nil(t, GetLobby(regular.LobbyID))
+	require.Equal(t, permanent, GetLobby(permanent.LobbyID))
+
+	RemoveLobby(permanent.LobbyID)
+	require.Empty(t, lobbies)
+}
+
+//nolint:paralleltest //this test is very stateful
+func TestScheduledLobbies(t *testing.T) {
+	createLobby := func(scheduledStart time.Time) *game.Lobby {
+		lobby := game.NewLobby("", "english", &game.EditableLobbySettings{
+			DrawingTime:  100,
+			Rounds:       10,
+			MaxPlayers:   10,
+			WordsPerTurn: 3,
+		}, nil, game.ChillScoring, game.ClassicMode)
+		lobby.ScheduledStart = scheduledStart
+		return lobby
+	}
+	upcoming := createLobby(time.Now().Add(time.Hour))
+	started := createLobby(time.Now().Add(-time.Hour))
+
+	AddLobby(upcoming)
+	AddLobby(started)
+
+	// Scheduled lobbies are kept until the start, even without players.
+	cleanupRoutineLogic(&config.LobbyCleanup{PlayerInactivityThreshold: time.Minute})
+	require.Equal(t, upcoming, GetLobby(upcoming.LobbyID))
+	require.Nil(t, GetLobby(started.LobbyID))
+
+	RemoveLobby(upcoming.LobbyID)
+	require.Empty(t, lobbies)
+}
+
+//nolint:paralleltest //this test is very stateful
+func TestTournamentLobbies(t *testing.T) {
+	template := game.NewLobby("", "english", &game.EditableLobbySettings{
+		DrawingTime:  100,
+		Rounds:       10,
+		MaxPlayers:   10,
+		WordsPerTurn: 3,
+	}, nil, game.ChillScoring, game.ClassicMode)
+	tournament := game.NewTournament(template, 2, 10)
+	for _, name := range []string{"first", "second"} {
+		_, err := tournament.Register(name)
+		require.NoError(t, err)
+	}
+	tournamentLobbies, err := tournament.Start()
+	require.NoError(t, err)
+
+	AddTournament(tournament)
+	for _, lobby := range tournamentLobbies {
+		AddLobby(lobby)
+	}
+	require.Equal(t, tournament, GetTournament(tournament.ID))
+
+	// Lobbies of unfinished tournaments are kept, even without players.
+	cleanupRoutineLogic(&config.LobbyCleanup{PlayerInactivityThreshold: 0})
+	require.Equal(t, tournament, GetTournament(tournament.ID))
+	require.Len(t, lobbies, len(tournamentLobbies))
+
+	RemoveTournament(tournament.ID)
+	require.Nil(t, GetTournament(tournament.ID))
+	require.Empty(t, lobbies)
+}
+
+//nolint:paralleltest //this test is very stateful
+func TestArchivedResults(t *testing.T) {
+	lobby := game.NewLobby("", "english", &game.EditableLobbySettings{
+		DrawingTime:  100,
+		Rounds:       10,
+		MaxPlayers:   10,
+		WordsPerTurn: 3,
+	}, nil, game.ChillScoring, game.ClassicMode)
+	AddLobby(lobby)
+	require.Nil(t, GetArchivedGameResults(lobby.LobbyID, uuid.Nil))
+
+	recent := &game.GameResults{LobbyID: "recent", EndTime: time.Now()}
+	expired := &game.GameResults{LobbyID: "expired", EndTime: time.Now().Add(-2 * time.Hour)}
+	protected := &game.GameResults{LobbyID: "protected", EndTime: time.Now()}
+	playerSession := uuid.Must(uuid.NewV4())
+	archivedResults[recent.LobbyID] = &archivedGameResults{results: recent}
+	archivedResults[expired.LobbyID] = &archivedGameResults{results: expired}
+	archivedResults[protected.LobbyID] = &archivedGameResults{
+		results:      protected,
+		userSessions: []uuid.UUID{playerSession},
+	}
+
+	cleanupRoutineLogic(&config.LobbyCleanup{ResultsRetention: time.Hour})
+	require.Nil(t, GetLobby(lobby.LobbyID))
+	require.Equal(t, recent, GetArchivedGameResults(recent.LobbyID, uuid.Nil))
+	require.Nil(t, GetArchivedGameResults(expired.LobbyID, uuid.Nil))
+
+	// Results of protected lobbies are only available to their players.
+	require.Nil(t, GetArchivedGameResults(protected.LobbyID, uuid.Nil))
+	require.Nil(t, GetArchivedGameResults(protected.LobbyID, uuid.Must(uuid.NewV4())))
+	require.Equal(t, protected, GetArchivedGameResults(protected.LobbyID, playerSession))
+
+	clear(archivedResults)
+}
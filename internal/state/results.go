@@ -0,0 +1,74 @@
+package state
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/scribble-rs/scribble.rs/internal/game"
+)
+
+// archivedGameResults are the results of the last finished game of a removed
+// lobby.
+type archivedGameResults struct {
+	results *game.GameResults
+	// userSessions contains the sessions of all players of password protected
+	// lobbies. As the credentials are gone with the lobby, only the players
+	// may still access the results. For other lobbies, this is nil.
+	userSessions []uuid.UUID
+}
+
+// archivedResults maps the IDs of removed lobbies to the results of their
+// last finished game. Access is guarded by the globalStateMutex.
+var archivedResults = make(map[string]*archivedGameResults)
+
+// GetArchivedGameResults returns the results of the last finished game of
+// the removed lobby with the given ID. Results of password protected lobbies
+// are only returned to their former players. If there are no accessible
+// results, nil is returned.
+func GetArchivedGameResults(id string, userSession uuid.UUID) *game.GameResults {
+	globalStateMutex.RLock()
+	defer globalStateMutex.RUnlock()
+
+	archived := archivedResults[id]
+	if archived == nil {
+		return nil
+	}
+	if archived.userSessions != nil && !slices.Contains(archived.userSessions, userSession) {
+		return nil
+	}
+
+	return archived.results
+}
+
+// archiveResults keeps the results of a lobby that is about to be removed.
+func archiveResults(lobby *game.Lobby) {
+	var archived *archivedGameResults
+	lobby.Synchronized(func() {
+		results := lobby.GameResults()
+		if results == nil {
+			return
+		}
+
+		archived = &archivedGameResults{results: results}
+		if lobby.HasPassword() {
+			archived.userSessions = make([]uuid.UUID, 0, len(lobby.GetPlayers()))
+			for _, player := range lobby.GetPlayers() {
+				archived.userSessions = append(archived.userSessions, player.GetUserSession())
+			}
+		}
+	})
+	if archived != nil {
+		archivedResults[lobby.LobbyID] = archived
+	}
+}
+
+// cleanupResults removes all archived results that are older than the
+// retention time.
+func cleanupResults(retention time.Duration) {
+	for id, archived := range archivedResults {
+		if time.Since(archived.results.EndTime) >= retention {
+			delete(archivedResults, id)
+		}
+	}
+}
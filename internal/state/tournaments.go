@@ -0,0 +1,82 @@
+package state
+
+import (
+	"github.com/scribble-rs/scribble.rs/internal/game"
+)
+
+// tournaments maps the IDs of all tournaments to the tournaments. Access is
+// guarded by the globalStateMutex.
+var tournaments = make(map[string]*game.Tournament)
+
+// AddTournament makes a tournament available for GetTournament calls. The
+// lobbies of a tournament have to be added separately once it has started.
+func AddTournament(tournament *game.Tournament) {
+	globalStateMutex.Lock()
+	defer globalStateMutex.Unlock()
+
+	tournaments[tournament.ID] = tournament
+}
+
+// GetTournament returns the tournament with the given ID or nil if none
+// could be found.
+func GetTournament(id string) *game.Tournament {
+	globalStateMutex.RLock()
+	defer globalStateMutex.RUnlock()
+
+	return tournaments[id]
+}
+
+// RemoveTournament deletes a tournament and all of its lobbies.
+func RemoveTournament(id string) {
+	globalStateMutex.Lock()
+	defer globalStateMutex.Unlock()
+
+	removeTournament(id)
+}
+
+func removeTournament(id string) {
+	tournament := tournaments[id]
+	if tournament == nil {
+		return
+	}
+
+	for _, lobby := range tournament.Lobbies() {
+		removeLobby(lobby.LobbyID)
+	}
+	delete(tournaments, id)
+}
+
+// cleanupTournaments removes abandoned tournaments, as well as finished
+// tournaments of which all lobbies have already been cleaned up.
+func cleanupTournaments() {
+	for id, tournament := range tournaments {
+		if tournament.IsAbandoned() {
+			removeTournament(id)
+			continue
+		}
+
+		if !tournament.IsFinished() {
+			continue
+		}
+
+		lobbiesLeft := false
+		for _, lobby := range tournament.Lobbies() {
+			if isLobbyAdded(lobby) {
+				lobbiesLeft = true
+				break
+			}
+		}
+		if !lobbiesLeft {
+			delete(tournaments, id)
+		}
+	}
+}
+
+func isLobbyAdded(lobby *game.Lobby) bool {
+	for _, otherLobby := range lobbies {
+		if otherLobby == lobby {
+			return true
+		}
+	}
+	return false
+}
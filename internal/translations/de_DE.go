@@ -8,6 +8,13 @@ func initGermanTranslation() {
 	translation.put("start-the-game", "Mach dich bereit!")
 	translation.put("force-start", "Start erzwingen")
 	translation.put("force-restart", "Neustart erzwingen")
+	translation.put("clone-lobby", "Revanche in neuer Lobby")
+	translation.put("join-new-lobby", "Neuer Lobby beitreten")
+	translation.put("lobby-cloned-title", "Neue Lobby")
+	translation.put("lobby-cloned-text", "Der Besitzer der Lobby hat eine neue Lobby mit denselben Einstellungen eröffnet.")
+	translation.put("series-history", "Vorherige Spiele")
+	translation.put("series-game", "Spiel %s")
+	translation.put("series-score", "Gesamt: %s")
 	translation.put("game-not-started-title", "Warte auf Spielstart")
 	translation.put("waiting-for-host-to-start", "Bitte warte bis der Lobby Besitzer das Spiel startet.")
 
@@ -39,6 +46,9 @@ func initGermanTranslation() {
 	translation.put("toggle-spectate", "Zuschauermodus aktivieren / deaktivieren")
 	translation.put("show-help", "Hilfe anzeigen")
 	translation.put("votekick-a-player", "Stimme dafür ab, einen Spieler rauszuwerfen")
+	translation.put("vote-skip-turn", "Stimme dafür ab, den Zug zu überspringen")
+	translation.put("skip-vote", "(%s/%s) Spieler haben dafür gestimmt, den Zug zu überspringen.")
+	translation.put("turn-skipped", "Der Zug wurde übersprungen, der Zeichner bekommt keine Punkte.")
 
 	translation.put("change-lobby-settings-tooltip", "Lobby-Einstellungen ändern")
 	translation.put("change-lobby-settings-title", "Lobby-Einstellungen")
@@ -49,6 +59,53 @@ func initGermanTranslation() {
 	translation.put("chill-alt", "Zwar wird schnell sein belohnt, aber der Fokus liegt hier eher auf Spaß.")
 	translation.put("competitive-alt", "Je schneller du bist, desto mehr Punkte bekommst du. Schnell sein lohnt sich also!")
 	translation.put("score-calculation", "Punktsystem")
+	translation.put("classic", "Klassisch")
+	translation.put("whiteboard", "Whiteboard")
+	translation.put("classic-alt", "Ein Spieler zeichnet ein Wort, alle anderen versuchen es zu erraten.")
+	translation.put("whiteboard-alt", "Keine Wörter, Runden oder Punkte. Alle können gleichzeitig auf der selben Leinwand zeichnen.")
+	translation.put("telephone", "Stille Post")
+	translation.put("telephone-alt", "Alle schreiben einen Begriff, der weitergegeben und abwechselnd gezeichnet und beschrieben wird. Am Ende werden alle Ergebnisse gezeigt.")
+	translation.put("game-mode", "Spielmodus")
+	translation.put("drawers-per-turn-setting", "Zeichner pro Zug")
+	translation.put("drawer-score-setting", "Punkte bei mehreren Zeichnern")
+	translation.put("drawer-score-duplicate", "Alle bekommen die vollen Punkte")
+	translation.put("drawer-score-split", "Punkte aufteilen")
+	translation.put("guess-time-cap-setting", "Restzeit nach erstem richtigen Tipp (0 = aus)")
+	translation.put("guessed-percentage-setting", "Zug beenden, sobald % erraten haben (0 = aus)")
+	translation.put("word-rerolls-setting", "Neue Wörter pro Zug")
+	translation.put("reroll-words", "Andere Wörter")
+	translation.put("drawer-idle-timeout-setting", "Zug beenden, wenn Zeichner Sekunden inaktiv ist (0 = aus)")
+	translation.put("idle-turns-setting", "Inaktive Züge bis zum Zuschauen (0 = aus)")
+	translation.put("min-ready-players-setting", "Spieler, die bereit sein müssen (0 = alle)")
+	translation.put("start-countdown-setting", "Countdown vor dem Start in Sekunden (0 = aus)")
+	translation.put("game-starts-in", "Das Spiel startet in (Sekunden):")
+	translation.put("drawer-idle", "Zug vorzeitig beendet, der Zeichner hat nichts gezeichnet.")
+	translation.put("idle-spectating", "Du schaust jetzt zu, da du eine Weile nicht aktiv warst.")
+	translation.put("draw-order-setting", "Zeichenreihenfolge")
+	translation.put("draw-order-join", "Beitrittsreihenfolge")
+	translation.put("draw-order-shuffled", "Einmal pro Spiel gemischt")
+	translation.put("draw-order-reshuffled", "Jede Runde neu gemischt")
+	translation.put("draw-order-lowest_score", "Niedrigste Punktzahl zeichnet als Nächstes")
+	translation.put("lobby-password-setting", "Passwort")
+	translation.put("lobby-password-placeholder", "Leer lassen, damit jeder mit dem Link beitreten kann")
+	translation.put("scheduled-start-setting", "Geplanter Start (optional)")
+	translation.put("scheduled-start-in", "Das Spiel startet automatisch in")
+	translation.put("lobby-password", "Passwort")
+	translation.put("permanent-room", "Dauerhafter Raum")
+	translation.put("lobby-password-required", "Diese Lobby ist durch ein Passwort geschützt.")
+	translation.put("lobby-password-wrong", "Das Passwort ist falsch.")
+	translation.put("too-many-password-attempts", "Zu viele falsche Passwörter, bitte versuche es in einer Minute erneut.")
+	translation.put("create-invite-link", "Einladungslink erstellen")
+	translation.put("invite-link-placeholder", "Einladungslinks funktionieren ohne Passwort und laufen ab.")
+	translation.put("lobby-code", "Lobby-Code")
+	translation.put("quick-play", "Schnelles Spiel")
+	translation.put("reveal-hint", "Diesen Buchstaben den Ratenden zeigen. Das kostet dich Punkte.")
+	translation.put("telephone-prompt", "Schreibe einen Begriff, den jemand anderes zeichnen soll")
+	translation.put("telephone-describe", "Beschreibe diese Zeichnung")
+	translation.put("telephone-draw", "Zeichne: {0}")
+	translation.put("telephone-submit", "Abgeben")
+	translation.put("telephone-submitted", "{0} von {1} Spielern sind fertig.")
+	translation.put("telephone-reveal", "Das ist aus euren Begriffen geworden!")
 	translation.put("word-language", "Sprache")
 	translation.put("drawing-time-setting", "Zeichenzeit")
 	translation.put("rounds-setting", "Runden")
@@ -58,6 +115,9 @@ func initGermanTranslation() {
 	translation.put("custom-words-info", "Gib hier deine Extrawörter ein und trenne einzelne Wörter mit einem Komma")
 	translation.put("custom-words-per-turn-setting", "Extrawörter pro Zug")
 	translation.put("players-per-ip-limit-setting", "Maximale Spieler pro IP")
+	translation.put("aspect-ratio-setting", "Seitenverhältnis der Zeichenfläche")
+	translation.put("min-brush-size-setting", "Minimale Pinselgröße")
+	translation.put("max-brush-size-setting", "Maximale Pinselgröße")
 	translation.put("save-settings", "Einstellungen Speichern")
 	translation.put("input-contains-invalid-data", "Deine Eingaben enthalten invalide Daten:")
 	translation.put("please-fix-invalid-input", "Bitte korrigiere deine Eingaben und versuche es erneut.")
@@ -8,6 +8,13 @@ func initEnglishTranslation() *Translation {
 	translation.put("start-the-game", "Ready up!")
 	translation.put("force-start", "Force Start")
 	translation.put("force-restart", "Force Restart")
+	translation.put("clone-lobby", "Rematch in new lobby")
+	translation.put("join-new-lobby", "Join new lobby")
+	translation.put("lobby-cloned-title", "New lobby")
+	translation.put("lobby-cloned-text", "The lobby owner has opened a new lobby with the same settings.")
+	translation.put("series-history", "Previous games")
+	translation.put("series-game", "Game %s")
+	translation.put("series-score", "Total: %s")
 	translation.put("game-not-started-title", "Game hasn't started")
 	translation.put("waiting-for-host-to-start", "Please wait for your lobby host to start the game.")
 	translation.put("click-to-homepage", "Click here to get back to the Homepage")
@@ -45,6 +52,9 @@ func initEnglishTranslation() *Translation {
 	translation.put("self-kicked", "You have been kicked")
 	translation.put("kick-vote", "(%s/%s) players voted to kick %s.")
 	translation.put("player-kicked", "Player has been kicked.")
+	translation.put("vote-skip-turn", "Vote to skip the turn")
+	translation.put("skip-vote", "(%s/%s) players voted to skip the turn.")
+	translation.put("turn-skipped", "The turn has been skipped, the drawer doesn't get any points.")
 	translation.put("owner-change", "%s is the new lobby owner.")
 
 	translation.put("change-lobby-settings-tooltip", "Change the lobby settings")
@@ -56,6 +66,53 @@ func initEnglishTranslation() *Translation {
 	translation.put("chill-alt", "While being fast is rewarded, it's not too bad if you are little slower.\nThe base score is rather high, focus on having fun!")
 	translation.put("competitive-alt", "The faster you are, the more points you will get.\nThe base score is a lot lower and the decline is faster.")
 	translation.put("score-calculation", "Scoring")
+	translation.put("classic", "Classic")
+	translation.put("whiteboard", "Whiteboard")
+	translation.put("classic-alt", "One player draws a word, everyone else tries to guess it.")
+	translation.put("whiteboard-alt", "No words, rounds or scores.\nEveryone can draw on the same canvas at the same time.")
+	translation.put("telephone", "Telephone")
+	translation.put("telephone-alt", "Everyone writes a prompt, which is passed on and alternately drawn and described.\nAll results are revealed at the end.")
+	translation.put("game-mode", "Game mode")
+	translation.put("drawers-per-turn-setting", "Drawers per turn")
+	translation.put("drawer-score-setting", "Score of multiple drawers")
+	translation.put("drawer-score-duplicate", "Everyone gets the full score")
+	translation.put("drawer-score-split", "Split the score")
+	translation.put("guess-time-cap-setting", "Seconds left after first correct guess (0 = off)")
+	translation.put("guessed-percentage-setting", "End turn once % guessed (0 = off)")
+	translation.put("word-rerolls-setting", "Word rerolls per turn")
+	translation.put("reroll-words", "Other words")
+	translation.put("drawer-idle-timeout-setting", "End turn if drawer is idle for seconds (0 = off)")
+	translation.put("idle-turns-setting", "Idle turns until spectating (0 = off)")
+	translation.put("min-ready-players-setting", "Players that have to be ready (0 = all)")
+	translation.put("start-countdown-setting", "Start countdown in seconds (0 = off)")
+	translation.put("game-starts-in", "The game starts in (seconds):")
+	translation.put("drawer-idle", "Turn ended early, the drawer didn't draw anything.")
+	translation.put("idle-spectating", "You have been moved to spectating, since you haven't been active for a while.")
+	translation.put("draw-order-setting", "Drawing order")
+	translation.put("draw-order-join", "Join order")
+	translation.put("draw-order-shuffled", "Shuffled once per game")
+	translation.put("draw-order-reshuffled", "Shuffled every round")
+	translation.put("draw-order-lowest_score", "Lowest score draws next")
+	translation.put("lobby-password-setting", "Password")
+	translation.put("lobby-password-placeholder", "Leave empty to let anyone with the link join")
+	translation.put("scheduled-start-setting", "Scheduled start (optional)")
+	translation.put("scheduled-start-in", "The game starts automatically in")
+	translation.put("lobby-password", "Password")
+	translation.put("permanent-room", "Permanent room")
+	translation.put("lobby-password-required", "This lobby is protected by a password.")
+	translation.put("lobby-password-wrong", "The password is wrong.")
+	translation.put("too-many-password-attempts", "Too many wrong passwords, please try again in a minute.")
+	translation.put("create-invite-link", "Create invite link")
+	translation.put("invite-link-placeholder", "Invite links work without the password and expire.")
+	translation.put("lobby-code", "Lobby code")
+	translation.put("quick-play", "Quick play")
+	translation.put("reveal-hint", "Reveal this letter to the guessers. This costs you points.")
+	translation.put("telephone-prompt", "Write a prompt for someone else to draw")
+	translation.put("telephone-describe", "Describe this drawing")
+	translation.put("telephone-draw", "Draw: {0}")
+	translation.put("telephone-submit", "Submit")
+	translation.put("telephone-submitted", "{0} of {1} players are done.")
+	translation.put("telephone-reveal", "Here is what became of your prompts!")
 	translation.put("word-language", "Language")
 	translation.put("drawing-time-setting", "Drawing Time")
 	translation.put("rounds-setting", "Rounds")
@@ -67,6 +124,9 @@ func initEnglishTranslation() *Translation {
 	translation.put("custom-words-per-turn-setting", "Custom Words Per Turn")
 	translation.put("players-per-ip-limit-setting", "Players per IP Limit")
 	translation.put("words-per-turn-setting", "Words Per Turn")
+	translation.put("aspect-ratio-setting", "Canvas Aspect Ratio")
+	translation.put("min-brush-size-setting", "Minimum Brush Size")
+	translation.put("max-brush-size-setting", "Maximum Brush Size")
 	translation.put("save-settings", "Save settings")
 	translation.put("input-contains-invalid-data", "Your input contains invalid data:")
 	translation.put("please-fix-invalid-input", "Correct the invalid input and try again.")